@@ -0,0 +1,81 @@
+package skiplist
+
+// DefaultArenaChunkSize is the number of nodes per chunk used when UseArena is
+// given a non-positive chunk size.
+const DefaultArenaChunkSize = 1024
+
+// nodeArena hands out nodes and towers carved from large chunks instead of
+// allocating each node and its tower on the heap. Nodes
+// released by Delete and Clear are kept on per-height free lists and reused by
+// the next insert of the same height.
+type nodeArena[T any] struct {
+	chunkSize int
+	nodes     []Node[T]
+	levels    []level[T]
+	free      [MaxLevelCap + 1][]*Node[T]
+}
+
+// UseArena switches the list to arena allocation: nodes and their towers are
+// carved from chunks of chunkSize nodes, cutting the heap allocation per Add to
+// an amortized fraction of one, and nodes removed by Delete, Clear and
+// range deletions are recycled.
+//
+// Because nodes are recycled, a *Node returned by a search must not be used
+// after its value has been deleted from the list. Nodes already in the list
+// keep their heap allocation and are recycled into the arena once removed.
+func (sl *SkipList[T]) UseArena(chunkSize int) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultArenaChunkSize
+	}
+	sl.arena = &nodeArena[T]{chunkSize: chunkSize}
+}
+
+// newNode allocates a node from the arena if the list has one, and from the
+// heap otherwise.
+func (sl *SkipList[T]) newNode(val T, forwards int) *Node[T] {
+	if sl.arena == nil {
+		return NewNode(val, forwards)
+	}
+	return sl.arena.alloc(val, forwards)
+}
+
+// releaseNode hands an unlinked node back to the arena, if the list has one.
+func (sl *SkipList[T]) releaseNode(node *Node[T]) {
+	if sl.arena != nil {
+		sl.arena.release(node)
+	}
+}
+
+func (a *nodeArena[T]) alloc(val T, forwards int) *Node[T] {
+	if free := a.free[forwards-1]; len(free) > 0 {
+		node := free[len(free)-1]
+		a.free[forwards-1] = free[:len(free)-1]
+		node.val = val
+		return node
+	}
+
+	if len(a.nodes) == 0 {
+		a.nodes = make([]Node[T], a.chunkSize)
+	}
+	node := &a.nodes[0]
+	a.nodes = a.nodes[1:]
+
+	// towers average two levels at p = 0.5, so size the tower chunks accordingly
+	if len(a.levels) < forwards {
+		a.levels = make([]level[T], max(2*a.chunkSize, forwards))
+	}
+	node.val = val
+	node.levels = a.levels[:forwards:forwards]
+	a.levels = a.levels[forwards:]
+
+	return node
+}
+
+func (a *nodeArena[T]) release(node *Node[T]) {
+	var zero T
+	node.val = zero
+	clear(node.levels)
+
+	height := len(node.levels)
+	a.free[height-1] = append(a.free[height-1], node)
+}
@@ -0,0 +1,94 @@
+package skiplist
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Arena allocation tests
+// ------------------------------------------------------------
+
+func TestArena_MatchesHeapAllocation(t *testing.T) {
+	heap := NewSkipList[int]()
+	arena := NewSkipList[int]()
+	arena.UseArena(16)
+
+	rng := rand.New(rand.NewSource(34))
+	for step := 0; step < 5000; step++ {
+		v := rng.Intn(500)
+		lvl := randomLevel()
+		switch rng.Intn(10) {
+		case 0:
+			heap.deleteBetween(func(x int) bool { return x < v }, func(x int) bool { return x < v+10 })
+			arena.deleteBetween(func(x int) bool { return x < v }, func(x int) bool { return x < v+10 })
+		case 1, 2, 3:
+			heap.Delete(v)
+			arena.Delete(v)
+		default:
+			heap.InsertAtLevel(v, lvl)
+			arena.InsertAtLevel(v, lvl)
+		}
+	}
+
+	want := collectLevelValues(heap)
+	got := collectLevelValues(arena)
+	if len(got) != len(want) {
+		t.Fatalf("level count mismatch: got %d, want %d", len(got), len(want))
+	}
+	for level := range want {
+		if !slicesEqual(got[level], want[level]) {
+			t.Fatalf("level %d mismatch:\n got %v\nwant %v", level, got[level], want[level])
+		}
+	}
+	for rank := 1; rank <= heap.Len(); rank++ {
+		h, _ := heap.SearchByRank(rank)
+		a, found := arena.SearchByRank(rank)
+		if !found || a.val != h.val {
+			t.Fatalf("rank %d: got %v, want %d", rank, a, h.val)
+		}
+	}
+}
+
+func TestArena_RecyclesDeletedNodes(t *testing.T) {
+	sl := NewSkipList[int]()
+	sl.UseArena(4)
+
+	sl.InsertAtLevel(10, 2)
+	node, _ := sl.SearchByValue(10)
+	sl.Delete(10)
+
+	sl.InsertAtLevel(20, 2)
+	reused, _ := sl.SearchByValue(20)
+	if reused != node {
+		t.Fatal("a deleted node should be reused by the next insert of the same height")
+	}
+	for i, lvl := range reused.levels {
+		if lvl.next != nil {
+			t.Fatalf("recycled node should not keep stale links, level %d points to %v", i, lvl.next.val)
+		}
+	}
+
+	sl.InsertAtLevel(30, 0)
+	sl.Clear()
+	if got := len(sl.arena.free[0]) + len(sl.arena.free[2]); got != 2 {
+		t.Fatalf("Clear should recycle every node, %d on the free lists", got)
+	}
+	if sl.Len() != 0 || sl.Contains(20) {
+		t.Fatal("list should be empty after Clear")
+	}
+}
+
+func TestArena_ReducesAllocations(t *testing.T) {
+	sl := NewSkipList[int]()
+	sl.UseArena(DefaultArenaChunkSize)
+
+	i := 0
+	allocs := testing.AllocsPerRun(10000, func() {
+		sl.Add(i)
+		i++
+	})
+	if allocs >= 0.1 {
+		t.Fatalf("arena Add should amortize allocations below 0.1 per op, got %v", allocs)
+	}
+}
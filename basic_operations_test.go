@@ -13,7 +13,7 @@ func TestAddInit(t *testing.T) {
 		t.Error("Skiplist's head cannot be nil")
 	}
 
-	if skipList.head.forward[0] != skipList.tail {
+	if skipList.head.levels[0].next != skipList.tail {
 		t.Error("Skiplist's first element is not connected to the last element")
 	}
 }
@@ -111,8 +111,8 @@ func TestMaxLevelAndNodeLevels(t *testing.T) {
 	}
 
 	// Check each node's level is within bounds and consistent
-	for curr := sl.head; curr != nil; curr = curr.forward[0] {
-		nodeLevel := len(curr.forward) - 1
+	for curr := sl.head; curr != nil; curr = curr.levels[0].next {
+		nodeLevel := len(curr.levels) - 1
 		if curr == sl.tail && nodeLevel != -1 {
 			t.Fatalf("last node should have 0 forward pointers")
 		}
@@ -140,9 +140,9 @@ func TestForwardPointersMonotonic(t *testing.T) {
 		sl.Add(v)
 	}
 
-	for curr := sl.head; curr != sl.tail; curr = curr.forward[0] {
-		for level := 0; level < len(curr.forward) && curr.forward[level] != nil; level++ {
-			next := curr.forward[level]
+	for curr := sl.head; curr != sl.tail; curr = curr.levels[0].next {
+		for level := 0; level < len(curr.levels) && curr.levels[level].next != nil; level++ {
+			next := curr.levels[level].next
 			if next.val < curr.val {
 				t.Fatalf("forward pointer at level %d is not monotonic: %d -> %d", level, curr.val, next.val)
 			}
@@ -342,10 +342,10 @@ func TestSearch(t *testing.T) {
 				if node.val != itemsToVerify[i] {
 					t.Fatalf("item %d not present in skip list at correct position", itemsToVerify[i])
 				}
-				if len(node.forward) == 0 {
+				if len(node.levels) == 0 {
 					t.Fatal("node reached end!")
 				}
-				node = node.forward[0]
+				node = node.levels[0].next
 			}
 		} else {
 			t.Fatalf("item %d not found in skip list", item)
@@ -377,3 +377,21 @@ func TestSearchAbsentElement(t *testing.T) {
 		t.Fatalf("item 0 found in skip list but should not be present")
 	}
 }
+
+// Deleting a missing value that sorts before existing ones must not touch the list
+func TestDeleteMissingValueWithSuccessor(t *testing.T) {
+	sl := NewSkipList[int]()
+	for _, v := range []int{10, 20, 30} {
+		sl.Add(v)
+	}
+
+	sl.Delete(5)
+	sl.Delete(15)
+
+	if sl.Len() != 3 {
+		t.Fatalf("Len should still be 3, got %d", sl.Len())
+	}
+	if rank, found := sl.GetRank(30); !found || rank != 3 {
+		t.Fatalf("rank of 30 should still be 3, got %d (%v)", rank, found)
+	}
+}
@@ -0,0 +1,200 @@
+package skiplist
+
+import "slices"
+
+// Batch collects inserts and deletions to be applied to a skip list as one
+// unit by Apply. The zero value is an empty batch ready to use.
+//
+// Operations take effect as if applied in the order they were recorded: when
+// several operations touch the same value, the last one wins, and a
+// DeleteRange removes values added earlier in the batch but not later.
+type Batch[T any] struct {
+	ops      []batchOp[T]
+	ranges   []batchRange[T]
+	validate func(ro ReadOnly[T]) error
+}
+
+type batchOp[T any] struct {
+	val T
+	del bool
+	seq int
+}
+
+type batchRange[T any] struct {
+	lo, hi T
+	seq    int
+}
+
+// undoEntry remembers a removed value and its tower level, so a rolled back
+// batch restores the list's exact shape.
+type undoEntry[T any] struct {
+	val T
+	lvl int
+}
+
+// Add records an insert of val.
+func (b *Batch[T]) Add(val T) {
+	b.ops = append(b.ops, batchOp[T]{val: val, seq: b.Len()})
+}
+
+// Delete records a deletion of val.
+func (b *Batch[T]) Delete(val T) {
+	b.ops = append(b.ops, batchOp[T]{val: val, del: true, seq: b.Len()})
+}
+
+// DeleteRange records a deletion of every value in [lo, hi).
+func (b *Batch[T]) DeleteRange(lo, hi T) {
+	b.ranges = append(b.ranges, batchRange[T]{lo: lo, hi: hi, seq: b.Len()})
+}
+
+// Validate sets a hook that Apply calls after applying the batch. If it
+// returns an error, Apply rolls the batch back and returns that error. The hook
+// sees the list as the batch left it and must not modify it.
+func (b *Batch[T]) Validate(fn func(ro ReadOnly[T]) error) {
+	b.validate = fn
+}
+
+// Len returns the number of operations recorded in the batch.
+func (b *Batch[T]) Len() int {
+	return len(b.ops) + len(b.ranges)
+}
+
+// Reset empties the batch, keeping its validation hook.
+func (b *Batch[T]) Reset() {
+	b.ops = b.ops[:0]
+	b.ranges = b.ranges[:0]
+}
+
+// Apply applies every operation in b to the list. Range deletions are applied
+// first; the remaining inserts and deletions are then applied in ascending
+// order with a finger search, so each one resumes from the position of the
+// previous one instead of searching from the head.
+//
+// If the batch's validation hook fails, the batch is rolled back and the error
+// is returned: the list holds the same values with the same tower heights as
+// before, although re-inserted values live in new nodes. If the hook panics,
+// the batch is rolled back the same way before the panic continues. Apply is
+// not safe for concurrent use; SyncSkipList.Apply makes a batch atomic to
+// readers.
+//
+// Time Complexity: O(k log n) worst case for k operations, and close to O(k +
+// log n) when the batch's values are clustered
+func (sl *SkipList[T]) Apply(b *Batch[T]) error {
+	// the undo log is only needed if the batch can be rolled back
+	track := b.validate != nil
+	var added []T
+	var removed []undoEntry[T]
+
+	for _, r := range b.ranges {
+		removed = sl.deleteRange(r.lo, r.hi, track, removed)
+	}
+
+	var f finger[T]
+	for i := range f.hierarchy {
+		f.hierarchy[i] = sl.head
+	}
+
+	for _, op := range b.pointOps(sl.comparator) {
+		sl.descend(&f, op.val, sl.climb(&f, op.val))
+
+		next := f.hierarchy[0].levels[0].next
+		exists := next != nil && sl.comparator(next.val, op.val) == 0
+		switch {
+		case op.del && exists:
+			if track {
+				removed = append(removed, undoEntry[T]{val: next.val, lvl: len(next.levels) - 1})
+			}
+			sl.unlink(&f, next)
+		case !op.del && !exists:
+			sl.link(&f, op.val, randomLevel())
+			if track {
+				added = append(added, op.val)
+			}
+		}
+	}
+
+	if !track {
+		return nil
+	}
+	rollback := func() {
+		for _, val := range added {
+			sl.Delete(val)
+		}
+		for _, e := range removed {
+			sl.InsertAtLevel(e.val, e.lvl)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			rollback()
+			panic(r)
+		}
+	}()
+	if err := b.validate(readOnly[T]{sl}); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
+
+// pointOps returns the inserts and deletions that decide the final state of
+// their value, sorted by value: the last operation on each value, unless a
+// later DeleteRange covers it.
+func (b *Batch[T]) pointOps(comparator Comparator[T]) []batchOp[T] {
+	ops := slices.Clone(b.ops)
+	slices.SortStableFunc(ops, func(a, b batchOp[T]) int {
+		return comparator(a.val, b.val)
+	})
+
+	out := ops[:0]
+	for i, op := range ops {
+		if i+1 < len(ops) && comparator(ops[i+1].val, op.val) == 0 {
+			continue
+		}
+		if !b.deletedLater(op, comparator) {
+			out = append(out, op)
+		}
+	}
+	return out
+}
+
+// deletedLater reports whether a DeleteRange recorded after op covers its value.
+func (b *Batch[T]) deletedLater(op batchOp[T], comparator Comparator[T]) bool {
+	for _, r := range b.ranges {
+		if r.seq > op.seq && comparator(r.lo, op.val) <= 0 && comparator(op.val, r.hi) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteRange removes the values in [lo, hi), appending them to removed if
+// track is set.
+func (sl *SkipList[T]) deleteRange(lo, hi T, track bool, removed []undoEntry[T]) []undoEntry[T] {
+	before := func(v T) bool { return sl.comparator(v, lo) < 0 }
+	within := func(v T) bool { return sl.comparator(v, hi) < 0 }
+
+	if track {
+		node, _ := sl.seek(before)
+		for ; node != nil && within(node.val); node = node.levels[0].next {
+			removed = append(removed, undoEntry[T]{val: node.val, lvl: len(node.levels) - 1})
+		}
+	}
+	sl.deleteBetween(before, within)
+	return removed
+}
+
+// climb returns the lowest level at which the finger's node is still the last
+// one before val. The finger's nodes at that level and above need not move, so
+// a finger search for val can descend from there.
+func (sl *SkipList[T]) climb(f *finger[T], val T) int {
+	top := 0
+	for top < sl.maxLevel {
+		next := f.hierarchy[top].levels[top].next
+		if next == nil || sl.comparator(next.val, val) >= 0 {
+			break
+		}
+		top++
+	}
+	return top
+}
@@ -0,0 +1,240 @@
+package skiplist
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Batch helpers
+// ------------------------------------------------------------
+
+// listShape describes every level of sl with the values and spans of its
+// nodes, so two lists with the same shape print identically.
+func listShape(sl *SkipList[int]) string {
+	var shape string
+	for level := 0; level <= MaxLevelCap; level++ {
+		shape += fmt.Sprintf("L%d head+%d", level, sl.head.levels[level].span)
+		for n := sl.head.levels[level].next; n != nil; n = n.levels[level].next {
+			shape += fmt.Sprintf(" %d+%d", n.val, n.levels[level].span)
+		}
+		shape += "\n"
+	}
+	return shape
+}
+
+// assertRanksConsistent checks that the spans at every level add up to the
+// true rank of each node.
+func assertRanksConsistent(t *testing.T, sl *SkipList[int]) {
+	t.Helper()
+	rankOf := map[*Node[int]]int{}
+	rank := 0
+	for n := sl.head.levels[0].next; n != nil; n = n.levels[0].next {
+		rank++
+		rankOf[n] = rank
+	}
+	if rank != sl.Len() {
+		t.Fatalf("level 0 has %d nodes, Len is %d", rank, sl.Len())
+	}
+
+	for level := 0; level <= sl.maxLevel; level++ {
+		pos := 0
+		for n := sl.head; n != nil; n = n.levels[level].next {
+			pos += n.levels[level].span
+			if next := n.levels[level].next; next != nil && rankOf[next] != pos {
+				t.Fatalf("level %d: span sum to %d is %d, want %d", level, next.val, pos, rankOf[next])
+			}
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// Batch tests
+// ------------------------------------------------------------
+
+func TestApply_PointAndRangeOperations(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 1; i <= 10; i++ {
+		sl.Add(i * 10)
+	}
+
+	var b Batch[int]
+	b.Add(35)
+	b.Delete(50)
+	b.DeleteRange(70, 90) // removes 70 and 80, keeps 90
+	b.Add(75)             // recorded after the range, so it survives
+	b.Add(15)
+	b.Delete(15) // last operation on a value wins
+	b.Add(20)    // already present
+	b.Delete(999)
+
+	if err := sl.Apply(&b); err != nil {
+		t.Fatalf("Apply returned %v", err)
+	}
+	assertOrder(t, sl, []int{10, 20, 30, 35, 40, 60, 75, 90, 100})
+	assertRanksConsistent(t, sl)
+}
+
+func TestApply_RangeOverridesEarlierAdds(t *testing.T) {
+	sl := NewSkipList[int]()
+	sl.Add(1)
+
+	var b Batch[int]
+	b.Add(5)
+	b.Add(8)
+	b.DeleteRange(0, 6)
+	b.Delete(8)
+	b.Add(8)
+
+	if err := sl.Apply(&b); err != nil {
+		t.Fatalf("Apply returned %v", err)
+	}
+	if got := collectLevelValues(sl)[0]; !slicesEqual(got, []int{8}) {
+		t.Fatalf("expected [8], got %v", got)
+	}
+}
+
+func TestApply_RollbackRestoresShape(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 0; i < 200; i++ {
+		sl.Add(i * 3)
+	}
+	before := listShape(sl)
+
+	errTooSmall := errors.New("too small")
+	var b Batch[int]
+	for i := 0; i < 50; i++ {
+		b.Delete(i * 6)
+		b.Add(i*6 + 1)
+	}
+	b.DeleteRange(300, 450)
+	b.Validate(func(ro ReadOnly[int]) error {
+		if ro.Contains(300) || !ro.Contains(1) {
+			t.Error("the hook should see the applied batch")
+		}
+		return errTooSmall
+	})
+
+	if err := sl.Apply(&b); !errors.Is(err, errTooSmall) {
+		t.Fatalf("Apply should return the hook's error, got %v", err)
+	}
+	if after := listShape(sl); after != before {
+		t.Fatalf("rolled back list differs from the original:\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+	if sl.Len() != 200 {
+		t.Fatalf("Len should be restored to 200, got %d", sl.Len())
+	}
+}
+
+func TestApply_RollbackOnPanickingHook(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 0; i < 100; i++ {
+		sl.Add(i * 2)
+	}
+	before := listShape(sl)
+
+	var b Batch[int]
+	for i := 0; i < 20; i++ {
+		b.Delete(i * 4)
+		b.Add(i*4 + 1)
+	}
+	b.DeleteRange(100, 150)
+	b.Validate(func(ro ReadOnly[int]) error {
+		panic("hook failed")
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "hook failed" {
+				t.Fatalf("Apply should re-panic with the hook's value, got %v", r)
+			}
+		}()
+		sl.Apply(&b)
+	}()
+	if after := listShape(sl); after != before {
+		t.Fatalf("list differs after a panicking hook:\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+	assertRanksConsistent(t, sl)
+}
+
+func TestApply_MatchesSequentialModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(40))
+	sl := NewSkipList[int]()
+	model := map[int]bool{}
+
+	for round := 0; round < 200; round++ {
+		var b Batch[int]
+		for i := rng.Intn(30); i > 0; i-- {
+			v := rng.Intn(500)
+			switch rng.Intn(5) {
+			case 0:
+				hi := v + rng.Intn(20)
+				b.DeleteRange(v, hi)
+				for k := range model {
+					if k >= v && k < hi {
+						delete(model, k)
+					}
+				}
+			case 1, 2:
+				b.Delete(v)
+				delete(model, v)
+			default:
+				b.Add(v)
+				model[v] = true
+			}
+		}
+		if err := sl.Apply(&b); err != nil {
+			t.Fatalf("Apply returned %v", err)
+		}
+
+		want := make([]int, 0, len(model))
+		for k := range model {
+			want = append(want, k)
+		}
+		sort.Ints(want)
+		if got := collectLevelValues(sl)[0]; !slicesEqual(got, want) {
+			t.Fatalf("round %d: got %v, want %v", round, got, want)
+		}
+		assertRanksConsistent(t, sl)
+	}
+}
+
+func TestSyncSkipList_ApplyIsAtomic(t *testing.T) {
+	s := NewSyncSkipList[int]()
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+
+	// every batch moves the whole set by 100, so readers must always see
+	// exactly 100 values within a window of 100
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for round := 0; round < 50; round++ {
+			var b Batch[int]
+			b.DeleteRange(round*100, (round+1)*100)
+			for i := (round + 1) * 100; i < (round+2)*100; i++ {
+				b.Add(i)
+			}
+			if err := s.Apply(&b); err != nil {
+				t.Errorf("Apply returned %v", err)
+			}
+		}
+	}()
+
+	for r := 0; r < 200; r++ {
+		s.View(func(ro ReadOnly[int]) {
+			first, _ := ro.SearchByRank(1)
+			last, _ := ro.SearchByRank(ro.Len())
+			if ro.Len() != 100 || last.Value()-first.Value() != 99 {
+				t.Errorf("reader saw a half-applied batch: Len %d", ro.Len())
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -2,6 +2,7 @@ package skiplist
 
 import (
 	"math/rand"
+	"sync"
 	"testing"
 )
 
@@ -18,6 +19,16 @@ func BenchmarkAdd(b *testing.B) {
 	}
 }
 
+// BenchmarkAdd_Arena benchmarks adding elements to an empty skip list using arena allocation
+func BenchmarkAdd_Arena(b *testing.B) {
+	sl := NewSkipList[int]()
+	sl.UseArena(DefaultArenaChunkSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sl.Add(i)
+	}
+}
+
 // BenchmarkAdd_Existing benchmarks adding elements to a skip list with existing elements
 func BenchmarkAdd_Existing(b *testing.B) {
 	sl := NewSkipList[int]()
@@ -233,6 +244,27 @@ func BenchmarkSearch_Size10000(b *testing.B) {
 	benchmarkSearchWithSize(b, 10000)
 }
 
+// BenchmarkSearch_Random1M benchmarks random lookups in a list too large for
+// the CPU caches, where every level step is a likely cache miss
+func BenchmarkSearch_Random1M(b *testing.B) {
+	const size = 1 << 20
+	sl := NewSkipList[int]()
+	for i := 0; i < size; i++ {
+		sl.Add(i)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	searchValues := make([]int, 1<<16)
+	for i := range searchValues {
+		searchValues[i] = rng.Intn(size)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = sl.SearchByValue(searchValues[i&(len(searchValues)-1)])
+	}
+}
+
 func benchmarkSearchWithSize(b *testing.B, size int) {
 	sl := NewSkipList[int]()
 	// Pre-populate
@@ -269,6 +301,87 @@ func BenchmarkMixedOperations(b *testing.B) {
 	}
 }
 
+// BenchmarkMixedOperations_Arena benchmarks the same mix with arena allocation,
+// where deleted nodes are recycled by later adds
+func BenchmarkMixedOperations_Arena(b *testing.B) {
+	sl := NewSkipList[int]()
+	sl.UseArena(DefaultArenaChunkSize)
+	size := 10000
+
+	// Pre-populate
+	for i := 0; i < size; i++ {
+		sl.Add(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Mix of operations
+		sl.Add(size + i)
+		_, _ = sl.SearchByValue(i % size)
+		sl.Delete(i % size)
+	}
+}
+
+// benchmarkBatchOps returns a list of size elements and 1000 clustered
+// operations for it: deletes of existing values and adds of new ones
+func benchmarkBatchOps(size int) (*SkipList[int], []int) {
+	sl := NewSkipList[int]()
+	for i := 0; i < size; i++ {
+		sl.Add(i * 2)
+	}
+	ops := make([]int, 1000)
+	for i := range ops {
+		ops[i] = size/2 + i
+	}
+	return sl, ops
+}
+
+// BenchmarkBatch_Individual benchmarks applying 1000 operations one call at a time
+func BenchmarkBatch_Individual(b *testing.B) {
+	sl, ops := benchmarkBatchOps(100000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range ops {
+			if v%2 == 0 {
+				sl.Delete(v)
+			} else {
+				sl.Add(v)
+			}
+		}
+		// undo, so every iteration starts from the same list
+		for _, v := range ops {
+			if v%2 == 0 {
+				sl.Add(v)
+			} else {
+				sl.Delete(v)
+			}
+		}
+	}
+}
+
+// BenchmarkBatch_Apply benchmarks applying the same operations with Apply
+func BenchmarkBatch_Apply(b *testing.B) {
+	sl, ops := benchmarkBatchOps(100000)
+
+	var batch, undo Batch[int]
+	for _, v := range ops {
+		if v%2 == 0 {
+			batch.Delete(v)
+			undo.Add(v)
+		} else {
+			batch.Add(v)
+			undo.Delete(v)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = sl.Apply(&batch)
+		_ = sl.Apply(&undo)
+	}
+}
+
 // BenchmarkSequentialAdd benchmarks adding elements in sequential order
 func BenchmarkSequentialAdd(b *testing.B) {
 	sl := NewSkipList[int]()
@@ -308,3 +421,70 @@ func BenchmarkStringOperations(b *testing.B) {
 		_, _ = sl.SearchByValue(searchVal)
 	}
 }
+
+// ------------------------------------------------------------
+// Concurrent Benchmarks
+// ------------------------------------------------------------
+
+// concurrentSet is the part of the API shared by the concurrent lists.
+type concurrentSet interface {
+	Add(val int)
+	Delete(val int)
+	Contains(val int) bool
+}
+
+type lazySet struct{ *LazySkipList[int] }
+
+func (s lazySet) Add(val int)    { s.LazySkipList.Add(val) }
+func (s lazySet) Delete(val int) { s.LazySkipList.Delete(val) }
+
+type lockFreeSet struct{ *ConcurrentSkipList[int] }
+
+func (s lockFreeSet) Add(val int)    { s.ConcurrentSkipList.Add(val) }
+func (s lockFreeSet) Delete(val int) { s.ConcurrentSkipList.Delete(val) }
+
+// benchmarkParallelWrites runs a write-heavy mix (40% Add, 40% Delete, 20%
+// Contains) over 100000 keys on all cores, as on an ingestion path
+func benchmarkParallelWrites(b *testing.B, set concurrentSet) {
+	const keys = 100000
+	for i := 0; i < keys; i += 2 {
+		set.Add(i)
+	}
+
+	var seed int64
+	var mu sync.Mutex
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		mu.Lock()
+		seed++
+		rng := rand.New(rand.NewSource(seed))
+		mu.Unlock()
+
+		for pb.Next() {
+			v := rng.Intn(keys)
+			switch op := rng.Intn(5); {
+			case op < 2:
+				set.Add(v)
+			case op < 4:
+				set.Delete(v)
+			default:
+				set.Contains(v)
+			}
+		}
+	})
+}
+
+// BenchmarkParallelWrites_Sync benchmarks the RWMutex-guarded SyncSkipList
+func BenchmarkParallelWrites_Sync(b *testing.B) {
+	benchmarkParallelWrites(b, NewSyncSkipList[int]())
+}
+
+// BenchmarkParallelWrites_Lazy benchmarks the fine-grained LazySkipList
+func BenchmarkParallelWrites_Lazy(b *testing.B) {
+	benchmarkParallelWrites(b, lazySet{NewLazySkipList[int]()})
+}
+
+// BenchmarkParallelWrites_LockFree benchmarks the lock-free ConcurrentSkipList
+func BenchmarkParallelWrites_LockFree(b *testing.B) {
+	benchmarkParallelWrites(b, lockFreeSet{NewConcurrentSkipList[int]()})
+}
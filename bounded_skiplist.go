@@ -0,0 +1,163 @@
+package skiplist
+
+import "cmp"
+
+// EvictionPolicy selects which end of a BoundedSkipList is evicted when it is full.
+type EvictionPolicy int
+
+const (
+	// EvictMin evicts the smallest element, keeping the largest ones (top-K).
+	EvictMin EvictionPolicy = iota
+	// EvictMax evicts the largest element, keeping the smallest ones (bottom-K).
+	EvictMax
+)
+
+// BoundedSkipList is a skip list that never holds more than a fixed number of
+// elements. Combined with SearchByRank it works as a streaming top-K (EvictMin)
+// or bottom-K (EvictMax) tracker.
+type BoundedSkipList[T any] struct {
+	list     *SkipList[T]
+	capacity int
+	policy   EvictionPolicy
+}
+
+// NewBounded creates a bounded skip list for ordered types holding at most
+// capacity elements. It panics if capacity is not positive.
+func NewBounded[T cmp.Ordered](capacity int, policy EvictionPolicy) *BoundedSkipList[T] {
+	return newBounded(NewSkipList[T](), capacity, policy)
+}
+
+// NewComparableBounded creates a bounded skip list for types that implement the
+// Comparable interface. It panics if capacity is not positive.
+func NewComparableBounded[T Comparable[T]](capacity int, policy EvictionPolicy) *BoundedSkipList[T] {
+	return newBounded(NewComparableSkipList[T](), capacity, policy)
+}
+
+func newBounded[T any](list *SkipList[T], capacity int, policy EvictionPolicy) *BoundedSkipList[T] {
+	if capacity <= 0 {
+		panic("skiplist: bounded capacity must be positive")
+	}
+
+	return &BoundedSkipList[T]{
+		list:     list,
+		capacity: capacity,
+		policy:   policy,
+	}
+}
+
+// Add inserts val, evicting an element from the policy's end if the list is
+// full. It returns the evicted element and true if one was evicted.
+//
+// When the list is full and val itself would be the one evicted, it is rejected
+// after a single comparison against the boundary node, before searching for its
+// position or allocating a tower; val is then returned as the evicted element.
+// Adding a duplicate evicts nothing.
+func (b *BoundedSkipList[T]) Add(val T) (T, bool) {
+	var zero T
+
+	if b.list.Len() < b.capacity {
+		b.list.Add(val)
+		return zero, false
+	}
+
+	boundary := b.boundary()
+	c := b.list.comparator(val, boundary.val)
+	if b.policy == EvictMax {
+		c = -c
+	}
+
+	// val sorts beyond the boundary, so it would be evicted right away
+	if c < 0 {
+		return val, true
+	}
+	if c == 0 {
+		return zero, false
+	}
+
+	before := b.list.Len()
+	b.list.Add(val)
+	if b.list.Len() == before {
+		return zero, false
+	}
+
+	evicted := boundary.val
+	b.list.Delete(evicted)
+	return evicted, true
+}
+
+// boundary returns the node that is evicted next. The list must not be empty.
+func (b *BoundedSkipList[T]) boundary() *Node[T] {
+	if b.policy == EvictMax {
+		return b.list.last()
+	}
+	return b.list.head.levels[0].next
+}
+
+// Min returns the smallest element, or false if the list is empty.
+func (b *BoundedSkipList[T]) Min() (T, bool) {
+	if node := b.list.head.levels[0].next; node != nil {
+		return node.val, true
+	}
+	var zero T
+	return zero, false
+}
+
+// Max returns the largest element, or false if the list is empty.
+func (b *BoundedSkipList[T]) Max() (T, bool) {
+	if node := b.list.last(); node != nil {
+		return node.val, true
+	}
+	var zero T
+	return zero, false
+}
+
+// Cap returns the maximum number of elements the list holds.
+func (b *BoundedSkipList[T]) Cap() int {
+	return b.capacity
+}
+
+// Len returns the number of elements in the list.
+func (b *BoundedSkipList[T]) Len() int {
+	return b.list.Len()
+}
+
+// IsEmpty returns true if the list contains no elements.
+func (b *BoundedSkipList[T]) IsEmpty() bool {
+	return b.list.IsEmpty()
+}
+
+// Contains checks if a value exists in the list.
+func (b *BoundedSkipList[T]) Contains(val T) bool {
+	return b.list.Contains(val)
+}
+
+// Delete removes a value from the list. No-op if the value doesn't exist.
+func (b *BoundedSkipList[T]) Delete(val T) {
+	b.list.Delete(val)
+}
+
+// SearchByValue searches for a value and returns the node and a boolean indicating if found.
+func (b *BoundedSkipList[T]) SearchByValue(val T) (*Node[T], bool) {
+	return b.list.SearchByValue(val)
+}
+
+// SearchByRank returns the element at the given 1-indexed ascending position.
+func (b *BoundedSkipList[T]) SearchByRank(rank int) (*Node[T], bool) {
+	return b.list.SearchByRank(rank)
+}
+
+// GetRank returns the 1-indexed ascending position of item.
+func (b *BoundedSkipList[T]) GetRank(item T) (int, bool) {
+	return b.list.GetRank(item)
+}
+
+// Range iterates over all elements in ascending order.
+// The function fn is called for each element. If fn returns false, iteration stops.
+func (b *BoundedSkipList[T]) Range(fn func(val T) bool) {
+	b.list.Range(fn)
+}
+
+// Clear removes all elements from the list.
+func (b *BoundedSkipList[T]) Clear() {
+	b.list.Clear()
+}
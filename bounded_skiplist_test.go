@@ -0,0 +1,119 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Bounded skip list tests
+// ------------------------------------------------------------
+
+func collectBounded(b *BoundedSkipList[int]) []int {
+	var vals []int
+	b.Range(func(v int) bool {
+		vals = append(vals, v)
+		return true
+	})
+	return vals
+}
+
+func TestBounded_EvictMin(t *testing.T) {
+	b := NewBounded[int](3, EvictMin)
+
+	for _, v := range []int{5, 1, 9} {
+		if _, evicted := b.Add(v); evicted {
+			t.Fatalf("Add(%d) should not evict before the list is full", v)
+		}
+	}
+
+	if got, evicted := b.Add(7); !evicted || got != 1 {
+		t.Fatalf("Add(7) should evict 1, got %d (%v)", got, evicted)
+	}
+	if got := collectBounded(b); !slicesEqual(got, []int{5, 7, 9}) {
+		t.Fatalf("unexpected contents %v", got)
+	}
+
+	// smaller than the minimum: rejected without touching the list
+	if got, evicted := b.Add(2); !evicted || got != 2 {
+		t.Fatalf("Add(2) should be rejected and reported as evicted, got %d (%v)", got, evicted)
+	}
+	if got := collectBounded(b); !slicesEqual(got, []int{5, 7, 9}) {
+		t.Fatalf("rejected insert changed contents: %v", got)
+	}
+}
+
+func TestBounded_EvictMax(t *testing.T) {
+	b := NewBounded[int](3, EvictMax)
+
+	for _, v := range []int{5, 1, 9} {
+		b.Add(v)
+	}
+
+	if got, evicted := b.Add(3); !evicted || got != 9 {
+		t.Fatalf("Add(3) should evict 9, got %d (%v)", got, evicted)
+	}
+	if got, evicted := b.Add(10); !evicted || got != 10 {
+		t.Fatalf("Add(10) should be rejected, got %d (%v)", got, evicted)
+	}
+	if got := collectBounded(b); !slicesEqual(got, []int{1, 3, 5}) {
+		t.Fatalf("unexpected contents %v", got)
+	}
+	if max, _ := b.Max(); max != 5 {
+		t.Fatalf("Max should be 5, got %d", max)
+	}
+}
+
+func TestBounded_DuplicatesDoNotEvict(t *testing.T) {
+	b := NewBounded[int](2, EvictMin)
+	b.Add(1)
+	b.Add(2)
+
+	for _, v := range []int{1, 2} {
+		if _, evicted := b.Add(v); evicted {
+			t.Fatalf("Add(%d) duplicate should not evict", v)
+		}
+	}
+	if b.Len() != 2 {
+		t.Fatalf("Len should be 2, got %d", b.Len())
+	}
+}
+
+func TestBounded_StreamingTopK(t *testing.T) {
+	const k = 10
+	b := NewBounded[int](k, EvictMin)
+	rng := rand.New(rand.NewSource(28))
+
+	seen := map[int]bool{}
+	for i := 0; i < 5000; i++ {
+		v := rng.Intn(100000)
+		seen[v] = true
+		b.Add(v)
+		if b.Len() > k {
+			t.Fatalf("bounded list exceeded capacity: %d", b.Len())
+		}
+	}
+
+	all := make([]int, 0, len(seen))
+	for v := range seen {
+		all = append(all, v)
+	}
+	sort.Ints(all)
+
+	if got := collectBounded(b); !slicesEqual(got, all[len(all)-k:]) {
+		t.Fatalf("top-%d mismatch: got %v, want %v", k, got, all[len(all)-k:])
+	}
+	if node, found := b.SearchByRank(k); !found || node.Value() != all[len(all)-1] {
+		t.Fatal("SearchByRank(k) should return the overall maximum")
+	}
+}
+
+func TestBounded_InvalidCapacityPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewBounded(0) should panic")
+		}
+	}()
+	NewBounded[int](0, EvictMin)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	skiplist "github.com/anchor54/SkipList"
+)
+
+type (
+	sortedSet  = skiplist.SortedSet[string, float64]
+	member     = skiplist.Z[string, float64]
+	scoreBound = skiplist.ScoreBound[float64]
+)
+
+// store holds the named sorted sets. Read-only commands share the lock so they
+// run in parallel; commands that modify a set run exclusively.
+type store struct {
+	mu   sync.RWMutex
+	sets map[string]*sortedSet
+}
+
+func newStore() *store {
+	return &store{sets: make(map[string]*sortedSet)}
+}
+
+type command struct {
+	// arity follows the Redis convention: the exact argument count including the
+	// command name, or -N for "at least N".
+	arity int
+	write bool
+	fn    func(s *store, w respWriter, args []string)
+}
+
+var commands = map[string]command{
+	"ping":          {arity: -1, fn: cmdPing},
+	"command":       {arity: -1, fn: cmdCommand},
+	"zadd":          {arity: -4, write: true, fn: cmdZAdd},
+	"zrem":          {arity: -3, write: true, fn: cmdZRem},
+	"zcard":         {arity: 2, fn: cmdZCard},
+	"zrank":         {arity: 3, fn: cmdZRank},
+	"zcount":        {arity: 4, fn: cmdZCount},
+	"zrange":        {arity: -4, fn: cmdZRange},
+	"zrangebyscore": {arity: -4, fn: cmdZRangeByScore},
+}
+
+// exec runs one command and writes its reply.
+func (s *store) exec(w respWriter, args []string) {
+	name := strings.ToLower(args[0])
+	cmd, ok := commands[name]
+	if !ok {
+		w.error("ERR unknown command '" + args[0] + "'")
+		return
+	}
+	if (cmd.arity > 0 && len(args) != cmd.arity) || (cmd.arity < 0 && len(args) < -cmd.arity) {
+		w.error("ERR wrong number of arguments for '" + name + "' command")
+		return
+	}
+
+	if cmd.write {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	cmd.fn(s, w, args)
+}
+
+func cmdPing(_ *store, w respWriter, args []string) {
+	switch len(args) {
+	case 1:
+		w.simple("PONG")
+	case 2:
+		w.bulk(args[1])
+	default:
+		w.error("ERR wrong number of arguments for 'ping' command")
+	}
+}
+
+// cmdCommand answers the COMMAND introspection redis-cli sends on connect.
+func cmdCommand(_ *store, w respWriter, _ []string) {
+	w.array(0)
+}
+
+// ZADD key [NX|XX] [GT|LT] [CH] score member [score member ...]
+func cmdZAdd(s *store, w respWriter, args []string) {
+	var flags skiplist.ZAddFlag
+	i := 2
+flagLoop:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			flags |= skiplist.ZAddNX
+		case "XX":
+			flags |= skiplist.ZAddXX
+		case "GT":
+			flags |= skiplist.ZAddGT
+		case "LT":
+			flags |= skiplist.ZAddLT
+		case "CH":
+			flags |= skiplist.ZAddCH
+		default:
+			break flagLoop
+		}
+	}
+
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		w.error("ERR syntax error")
+		return
+	}
+
+	members := make([]member, 0, len(pairs)/2)
+	for j := 0; j < len(pairs); j += 2 {
+		score, ok := parseScore(pairs[j])
+		if !ok {
+			w.error("ERR value is not a valid float")
+			return
+		}
+		members = append(members, member{Member: pairs[j+1], Score: score})
+	}
+
+	set := s.sets[args[1]]
+	if set == nil {
+		set = skiplist.NewSortedSet[string, float64]()
+	}
+	n, err := set.ZAdd(flags, members...)
+	if err != nil {
+		w.error("ERR GT, LT, and/or NX options at the same time are not compatible")
+		return
+	}
+	if set.ZCard() > 0 {
+		s.sets[args[1]] = set
+	}
+	w.integer(n)
+}
+
+// ZREM key member [member ...]
+func cmdZRem(s *store, w respWriter, args []string) {
+	set := s.sets[args[1]]
+	if set == nil {
+		w.integer(0)
+		return
+	}
+
+	n := set.ZRem(args[2:]...)
+	if set.ZCard() == 0 {
+		delete(s.sets, args[1])
+	}
+	w.integer(n)
+}
+
+// ZCARD key
+func cmdZCard(s *store, w respWriter, args []string) {
+	if set := s.sets[args[1]]; set != nil {
+		w.integer(set.ZCard())
+		return
+	}
+	w.integer(0)
+}
+
+// ZRANK key member
+func cmdZRank(s *store, w respWriter, args []string) {
+	if set := s.sets[args[1]]; set != nil {
+		if rank, ok := set.ZRank(args[2]); ok {
+			w.integer(rank)
+			return
+		}
+	}
+	w.null()
+}
+
+// ZCOUNT key min max
+func cmdZCount(s *store, w respWriter, args []string) {
+	min, minOK := parseBound(args[2])
+	max, maxOK := parseBound(args[3])
+	if !minOK || !maxOK {
+		w.error("ERR min or max is not a float")
+		return
+	}
+
+	if set := s.sets[args[1]]; set != nil {
+		w.integer(set.ZCount(min, max))
+		return
+	}
+	w.integer(0)
+}
+
+// ZRANGE key start stop [WITHSCORES]
+func cmdZRange(s *store, w respWriter, args []string) {
+	start, err1 := strconv.Atoi(args[2])
+	stop, err2 := strconv.Atoi(args[3])
+	if err1 != nil || err2 != nil {
+		w.error("ERR value is not an integer or out of range")
+		return
+	}
+
+	withScores := false
+	for _, opt := range args[4:] {
+		if strings.ToUpper(opt) != "WITHSCORES" {
+			w.error("ERR syntax error")
+			return
+		}
+		withScores = true
+	}
+
+	var result []member
+	if set := s.sets[args[1]]; set != nil {
+		result = set.ZRange(start, stop)
+	}
+	writeMembers(w, result, withScores)
+}
+
+// ZRANGEBYSCORE key min max [WITHSCORES] [LIMIT offset count]
+func cmdZRangeByScore(s *store, w respWriter, args []string) {
+	min, minOK := parseBound(args[2])
+	max, maxOK := parseBound(args[3])
+	if !minOK || !maxOK {
+		w.error("ERR min or max is not a float")
+		return
+	}
+
+	withScores := false
+	offset, count := 0, -1
+	for i := 4; i < len(args); i++ {
+		switch {
+		case strings.EqualFold(args[i], "WITHSCORES"):
+			withScores = true
+		case strings.EqualFold(args[i], "LIMIT") && i+2 < len(args):
+			var err1, err2 error
+			offset, err1 = strconv.Atoi(args[i+1])
+			count, err2 = strconv.Atoi(args[i+2])
+			if err1 != nil || err2 != nil {
+				w.error("ERR value is not an integer or out of range")
+				return
+			}
+			i += 2
+		default:
+			w.error("ERR syntax error")
+			return
+		}
+	}
+
+	var result []member
+	if set := s.sets[args[1]]; set != nil && offset >= 0 {
+		result = set.ZRangeByScore(min, max, offset, count)
+	}
+	writeMembers(w, result, withScores)
+}
+
+func writeMembers(w respWriter, members []member, withScores bool) {
+	if withScores {
+		w.array(2 * len(members))
+	} else {
+		w.array(len(members))
+	}
+
+	for _, m := range members {
+		w.bulk(m.Member)
+		if withScores {
+			w.bulk(formatScore(m.Score))
+		}
+	}
+}
+
+func parseScore(s string) (float64, bool) {
+	switch strings.ToLower(s) {
+	case "inf", "+inf":
+		return math.Inf(1), true
+	case "-inf":
+		return math.Inf(-1), true
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// parseBound parses a ZRANGEBYSCORE-style bound: a score, optionally prefixed
+// with "(" to make it exclusive, or -inf/+inf.
+func parseBound(s string) (scoreBound, bool) {
+	exclusive := strings.HasPrefix(s, "(")
+	if exclusive {
+		s = s[1:]
+	}
+
+	f, ok := parseScore(s)
+	if !ok {
+		return scoreBound{}, false
+	}
+	return scoreBound{Value: f, Exclusive: exclusive}, true
+}
+
+func formatScore(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
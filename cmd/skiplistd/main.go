@@ -0,0 +1,61 @@
+// Command skiplistd serves named skip-list sorted sets over the Redis RESP2
+// protocol, so redis-cli and existing Redis client libraries can use them.
+//
+// Supported commands: ZADD, ZRANGE, ZRANK, ZREM, ZCARD, ZCOUNT, ZRANGEBYSCORE,
+// plus PING and QUIT.
+//
+// Usage:
+//
+//	skiplistd -addr 127.0.0.1:6380
+//	redis-cli -p 6380 ZADD board 100 alice 90 bob
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:6380", "address to listen on")
+	grace := flag.Duration("shutdown-timeout", 10*time.Second, "time allowed for connections to finish on shutdown")
+	flag.Parse()
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("skiplistd: %v", err)
+	}
+	log.Printf("skiplistd: listening on %s", ln.Addr())
+
+	srv := newServer()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.serve(ln)
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-errc:
+		log.Fatalf("skiplistd: %v", err)
+	case <-ctx.Done():
+	}
+
+	log.Printf("skiplistd: shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+
+	if err := srv.shutdown(shutdownCtx); err != nil {
+		log.Printf("skiplistd: forced shutdown: %v", err)
+	}
+	if err := <-errc; err != nil && !errors.Is(err, errServerClosed) {
+		log.Printf("skiplistd: %v", err)
+	}
+}
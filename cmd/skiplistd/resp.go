@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxBulkLen   = 64 << 20
+	maxArrayLen  = 1 << 20
+	maxInlineLen = 64 << 10
+
+	// bulkChunk bounds how much of a bulk string is allocated ahead of the
+	// bytes that have arrived, so a declared length costs nothing until the
+	// client sends the data.
+	bulkChunk = 64 << 10
+)
+
+// errProtocol is returned for malformed RESP input. The connection cannot be
+// resynchronized afterwards, so it is closed after reporting the error.
+var errProtocol = errors.New("Protocol error")
+
+// errInlineTooBig is returned for a line longer than maxInlineLen, so a client
+// that never sends a newline cannot make the server buffer without limit.
+var errInlineTooBig = fmt.Errorf("%w: too big inline request", errProtocol)
+
+// readCommand reads one client command, either as a RESP array of bulk strings
+// or as an inline command line as sent by telnet.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > maxArrayLen {
+		return nil, errProtocol
+	}
+
+	// args grows as arguments arrive rather than by the declared count
+	var args []string
+	for i := 0; i < n; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errProtocol
+		}
+
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, errProtocol
+		}
+
+		buf, err := readBulk(r, size+2)
+		if err != nil {
+			return nil, err
+		}
+		if buf[size] != '\r' || buf[size+1] != '\n' {
+			return nil, errProtocol
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// readBulk reads exactly n bytes, growing the buffer by at most bulkChunk
+// bytes ahead of the data read.
+func readBulk(r *bufio.Reader, n int) ([]byte, error) {
+	buf := make([]byte, 0, min(n, bulkChunk))
+	for len(buf) < n {
+		step := min(n-len(buf), bulkChunk)
+		buf = slices.Grow(buf, step)
+		if _, err := io.ReadFull(r, buf[len(buf):len(buf)+step]); err != nil {
+			return nil, err
+		}
+		buf = buf[:len(buf)+step]
+	}
+	return buf, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	var buf []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		buf = append(buf, chunk...)
+		if len(buf) > maxInlineLen+2 {
+			return "", errInlineTooBig
+		}
+		if err == nil {
+			break
+		}
+		if err != bufio.ErrBufferFull {
+			return "", err
+		}
+	}
+
+	line := string(buf)
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+// respWriter encodes RESP2 replies.
+type respWriter struct {
+	*bufio.Writer
+}
+
+func (w respWriter) simple(s string) {
+	w.WriteByte('+')
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func (w respWriter) error(msg string) {
+	w.WriteByte('-')
+	w.WriteString(msg)
+	w.WriteString("\r\n")
+}
+
+func (w respWriter) integer(n int) {
+	w.WriteByte(':')
+	w.WriteString(strconv.Itoa(n))
+	w.WriteString("\r\n")
+}
+
+func (w respWriter) bulk(s string) {
+	w.WriteByte('$')
+	w.WriteString(strconv.Itoa(len(s)))
+	w.WriteString("\r\n")
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func (w respWriter) null() {
+	w.WriteString("$-1\r\n")
+}
+
+func (w respWriter) array(n int) {
+	w.WriteByte('*')
+	w.WriteString(strconv.Itoa(n))
+	w.WriteString("\r\n")
+}
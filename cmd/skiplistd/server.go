@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errServerClosed is returned by serve after shutdown has been called.
+var errServerClosed = errors.New("skiplistd: server closed")
+
+// server accepts RESP2 connections and runs their commands against a shared store.
+type server struct {
+	store *store
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closing  bool
+	wg       sync.WaitGroup
+}
+
+func newServer() *server {
+	return &server{
+		store: newStore(),
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+// serve accepts connections on ln until shutdown is called.
+func (s *server) serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		ln.Close()
+		return errServerClosed
+	}
+	s.listener = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closing := s.closing
+			s.mu.Unlock()
+			if closing {
+				return errServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closing {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// shutdown stops accepting connections, lets every connection finish the
+// command it is running, and waits for them to close. If ctx expires first the
+// remaining connections are closed forcibly.
+func (s *server) shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	// wake up connections blocked waiting for the next command
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := respWriter{bufio.NewWriter(conn)}
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				w.error("ERR " + err.Error())
+			}
+			// replies to pipelined commands that already ran may still be
+			// buffered, whether the read failed on a partial command, at EOF
+			// or on the deadline set by shutdown
+			w.Flush()
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		if strings.EqualFold(args[0], "quit") {
+			w.simple("OK")
+			w.Flush()
+			return
+		}
+		s.store.exec(w, args)
+
+		// batch replies to pipelined commands into one write
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
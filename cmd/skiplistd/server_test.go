@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ------------------------------------------------------------
+// Test client
+// ------------------------------------------------------------
+
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func startServer(t *testing.T) (*server, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	srv := newServer()
+	go srv.serve(ln)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.shutdown(ctx)
+	})
+	return srv, ln.Addr().String()
+}
+
+func dial(t *testing.T, addr string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *testClient) send(args ...string) {
+	c.t.Helper()
+	msg := fmt.Sprintf("*%d\r\n", len(args))
+	for _, a := range args {
+		msg += fmt.Sprintf("$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := c.conn.Write([]byte(msg)); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+// reply reads one reply: strings for simple and bulk strings, int for integers,
+// error for errors, nil for a null bulk string and []any for arrays.
+func (c *testClient) reply() any {
+	c.t.Helper()
+	line, err := readLine(c.r)
+	if err != nil {
+		c.t.Fatalf("read reply: %v", err)
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return errors.New(line[1:])
+	case ':':
+		n, _ := strconv.Atoi(line[1:])
+		return n
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		if n < 0 {
+			return nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			c.t.Fatalf("read bulk: %v", err)
+		}
+		return string(buf[:n])
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		out := make([]any, n)
+		for i := range out {
+			out[i] = c.reply()
+		}
+		return out
+	}
+	c.t.Fatalf("unexpected reply %q", line)
+	return nil
+}
+
+func (c *testClient) do(want any, args ...string) {
+	c.t.Helper()
+	c.send(args...)
+	got := c.reply()
+	if err, ok := got.(error); ok {
+		got = "ERR:" + err.Error()
+	}
+	if !reflect.DeepEqual(got, want) {
+		c.t.Fatalf("%v: got %#v, want %#v", args, got, want)
+	}
+}
+
+// ------------------------------------------------------------
+// Server tests
+// ------------------------------------------------------------
+
+func TestServer_SortedSetCommands(t *testing.T) {
+	_, addr := startServer(t)
+	c := dial(t, addr)
+
+	c.do("PONG", "PING")
+	c.do(3, "ZADD", "board", "100", "alice", "90", "bob", "120", "carol")
+	c.do(0, "ZADD", "board", "NX", "1", "alice")
+	c.do(1, "ZADD", "board", "XX", "CH", "95", "bob")
+	c.do(3, "ZCARD", "board")
+	c.do(0, "ZCARD", "missing")
+	c.do(1, "ZRANK", "board", "alice")
+	c.do(nil, "ZRANK", "board", "nobody")
+	c.do([]any{"bob", "alice", "carol"}, "ZRANGE", "board", "0", "-1")
+	c.do([]any{"carol", "120"}, "ZRANGE", "board", "-1", "-1", "WITHSCORES")
+	c.do(2, "ZCOUNT", "board", "(95", "+inf")
+	c.do([]any{"alice", "carol"}, "ZRANGEBYSCORE", "board", "(95", "inf")
+	c.do([]any{"alice", "100"}, "ZRANGEBYSCORE", "board", "-inf", "+inf", "WITHSCORES", "LIMIT", "1", "1")
+	c.do(2, "ZREM", "board", "alice", "bob", "nobody")
+	c.do(1, "ZCARD", "board")
+}
+
+func TestServer_Errors(t *testing.T) {
+	_, addr := startServer(t)
+	c := dial(t, addr)
+
+	c.do("ERR:ERR unknown command 'FOO'", "FOO")
+	c.do("ERR:ERR wrong number of arguments for 'zcard' command", "ZCARD")
+	c.do("ERR:ERR value is not a valid float", "ZADD", "k", "abc", "m")
+	c.do("ERR:ERR syntax error", "ZADD", "k", "NX", "1")
+	c.do("ERR:ERR min or max is not a float", "ZCOUNT", "k", "x", "1")
+	c.do("ERR:ERR GT, LT, and/or NX options at the same time are not compatible", "ZADD", "k", "NX", "GT", "1", "m")
+	c.do(0, "ZCARD", "k")
+}
+
+func TestServer_InlineAndPipelinedCommands(t *testing.T) {
+	_, addr := startServer(t)
+	c := dial(t, addr)
+
+	if _, err := c.conn.Write([]byte("ZADD k 1 a\r\nZADD k 2 b\r\nZCARD k\r\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, want := range []any{1, 1, 2} {
+		if got := c.reply(); got != want {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+	}
+}
+
+func TestReadCommand_RejectsTooBigInlineRequest(t *testing.T) {
+	long := strings.Repeat("a", maxInlineLen+1) + "\r\n"
+	if _, err := readCommand(bufio.NewReader(strings.NewReader(long))); !errors.Is(err, errInlineTooBig) {
+		t.Fatalf("expected errInlineTooBig, got %v", err)
+	}
+
+	// a line without a newline is rejected once it passes the limit, rather
+	// than buffered until one arrives
+	unterminated := strings.Repeat("a", 2*maxInlineLen)
+	if _, err := readCommand(bufio.NewReader(strings.NewReader(unterminated))); !errors.Is(err, errInlineTooBig) {
+		t.Fatalf("expected errInlineTooBig, got %v", err)
+	}
+
+	fits := strings.Repeat("a", maxInlineLen) + "\r\n"
+	if args, err := readCommand(bufio.NewReader(strings.NewReader(fits))); err != nil || len(args) != 1 {
+		t.Fatalf("a line at the limit should be accepted, got %d args and %v", len(args), err)
+	}
+}
+
+func TestReadCommand_DoesNotReserveDeclaredSizes(t *testing.T) {
+	// headers that declare the largest array and bulk string, with no body
+	for _, header := range []string{
+		fmt.Sprintf("*%d\r\n", maxArrayLen),
+		fmt.Sprintf("*1\r\n$%d\r\n", maxBulkLen),
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if _, err := readCommand(bufio.NewReader(strings.NewReader(header))); err == nil {
+			t.Fatalf("%q: expected an error for the missing body", header)
+		}
+		runtime.ReadMemStats(&after)
+		if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+			t.Fatalf("%q: allocated %d bytes before any body arrived", header, alloc)
+		}
+	}
+}
+
+func TestServer_ConcurrentClients(t *testing.T) {
+	_, addr := startServer(t)
+
+	const clients, perClient = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		c := dial(t, addr)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perClient; j++ {
+				c.send("ZADD", "shared", strconv.Itoa(j), fmt.Sprintf("m-%d-%d", i, j))
+				c.reply()
+				c.send("ZRANGE", "shared", "0", "5")
+				c.reply()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	dial(t, addr).do(clients*perClient, "ZCARD", "shared")
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := newServer()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.serve(ln) }()
+
+	c := dial(t, ln.Addr().String())
+	c.do("OK", "QUIT")
+
+	idle := dial(t, ln.Addr().String())
+	idle.do("PONG", "PING")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-errc; !errors.Is(err, errServerClosed) {
+		t.Fatalf("serve should return errServerClosed, got %v", err)
+	}
+
+	// the idle connection is closed by the server
+	idle.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := idle.r.ReadByte(); err == nil {
+		t.Fatal("idle connection should be closed after shutdown")
+	}
+	if _, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+		t.Fatal("listener should be closed after shutdown")
+	}
+}
+
+func TestServer_FlushesRepliesBeforePartialCommandAtEOF(t *testing.T) {
+	_, addr := startServer(t)
+	c := dial(t, addr)
+
+	if _, err := c.conn.Write([]byte("ZADD k 1 a\r\nZCARD k\r\n*2\r\n$5\r\nZC")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	c.conn.(*net.TCPConn).CloseWrite()
+	for _, want := range []any{1, 1} {
+		if got := c.reply(); got != want {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+	}
+}
+
+func TestServer_ShutdownFlushesPipelinedReplies(t *testing.T) {
+	srv, addr := startServer(t)
+	c := dial(t, addr)
+
+	// two complete commands and the start of a third, which never arrives
+	if _, err := c.conn.Write([]byte("ZADD k 1 a\r\nZADD k 2 b\r\n*2\r\n$5\r\nZC")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(time.Millisecond) {
+		srv.store.mu.RLock()
+		z := srv.store.sets["k"]
+		done := z != nil && z.ZCard() == 2
+		srv.store.mu.RUnlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the pipelined commands did not run")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	for _, want := range []any{1, 1} {
+		if got := c.reply(); got != want {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+	}
+}
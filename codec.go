@@ -0,0 +1,316 @@
+package skiplist
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Errors returned when decoding a skip list.
+var (
+	// ErrNoCodec is returned when binary encoding is used on a list whose
+	// element type has no built-in codec and none was set with UseCodec.
+	ErrNoCodec = errors.New("skiplist: no codec for element type")
+	// ErrNotSorted is returned when decoded elements are out of order or
+	// contain duplicates.
+	ErrNotSorted = errors.New("skiplist: input is not strictly ascending")
+	// ErrNoComparator is returned when decoding into a zero SkipList whose
+	// element type is not an ordered basic type.
+	ErrNoComparator = errors.New("skiplist: no comparator for element type")
+)
+
+// binaryVersion is the version byte that starts the MarshalBinary format:
+// the version, the element count as a uvarint, then every element in
+// ascending order as a uvarint length followed by its codec encoding.
+const binaryVersion = 1
+
+// Codec converts single elements to and from bytes for MarshalBinary,
+// UnmarshalBinary and gob encoding. Lists of integer, float, string and []byte
+// kinds have a built-in codec; other element types need one set with UseCodec.
+type Codec[T any] interface {
+	Encode(val T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// UseCodec sets the codec used to encode and decode elements in binary form.
+func (sl *SkipList[T]) UseCodec(codec Codec[T]) {
+	sl.codec = codec
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It encodes the elements
+// in ascending order; tower heights are not preserved.
+func (sl *SkipList[T]) MarshalBinary() ([]byte, error) {
+	codec, err := sl.elementCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := []byte{binaryVersion}
+	buf = binary.AppendUvarint(buf, uint64(sl.Len()))
+	for node := sl.firstNode(); node != nil; node = node.levels[0].next {
+		data, err := codec.Encode(node.val)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the
+// list's contents with the decoded elements, which must be strictly ascending.
+// On error the list is left unchanged.
+func (sl *SkipList[T]) UnmarshalBinary(data []byte) error {
+	if err := sl.ensureComparator(); err != nil {
+		return err
+	}
+	codec, err := sl.elementCodec()
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 || data[0] != binaryVersion {
+		return fmt.Errorf("skiplist: unsupported binary format version")
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return fmt.Errorf("skiplist: corrupt element count")
+	}
+	data = data[n:]
+
+	vals := make([]T, 0, count)
+	for i := uint64(0); i < count; i++ {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return fmt.Errorf("skiplist: corrupt element %d", i)
+		}
+		val, err := codec.Decode(data[n : n+int(size)])
+		if err != nil {
+			return fmt.Errorf("skiplist: element %d: %w", i, err)
+		}
+		vals = append(vals, val)
+		data = data[n+int(size):]
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("skiplist: %d trailing bytes", len(data))
+	}
+
+	return sl.buildFromSorted(vals)
+}
+
+// MarshalJSON implements json.Marshaler, encoding the list as a JSON array of
+// its elements in ascending order.
+func (sl *SkipList[T]) MarshalJSON() ([]byte, error) {
+	vals := make([]T, 0, sl.Len())
+	for node := sl.firstNode(); node != nil; node = node.levels[0].next {
+		vals = append(vals, node.val)
+	}
+	return json.Marshal(vals)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The array must be strictly
+// ascending. On error the list is left unchanged. Like the encoding/json
+// decoders for slices and maps, it treats null as a no-op and leaves the list
+// as it was.
+func (sl *SkipList[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if err := sl.ensureComparator(); err != nil {
+		return err
+	}
+
+	var vals []T
+	if err := json.Unmarshal(data, &vals); err != nil {
+		return err
+	}
+	return sl.buildFromSorted(vals)
+}
+
+// GobEncode implements gob.GobEncoder using the binary format.
+func (sl *SkipList[T]) GobEncode() ([]byte, error) {
+	return sl.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary format.
+func (sl *SkipList[T]) GobDecode(data []byte) error {
+	return sl.UnmarshalBinary(data)
+}
+
+// firstNode returns the node holding the smallest value, or nil. It tolerates
+// a zero SkipList.
+func (sl *SkipList[T]) firstNode() *Node[T] {
+	if sl.head == nil {
+		return nil
+	}
+	return sl.head.levels[0].next
+}
+
+// buildFromSorted replaces the list's contents with vals in O(n), linking each
+// new node after the last node of every level it reaches instead of searching
+// for its position. vals must be strictly ascending.
+func (sl *SkipList[T]) buildFromSorted(vals []T) error {
+	for i := 1; i < len(vals); i++ {
+		if sl.comparator(vals[i-1], vals[i]) >= 0 {
+			return fmt.Errorf("%w: element %d", ErrNotSorted, i)
+		}
+	}
+
+	sl.Clear()
+
+	var last [MaxLevelCap + 1]*Node[T]
+	var lastRank [MaxLevelCap + 1]int
+	for i := range last {
+		last[i] = sl.head
+	}
+
+	for i, val := range vals {
+		rank := i + 1
+		lvl := randomLevel()
+		node := sl.newNode(val, lvl+1)
+
+		for l := 0; l <= lvl; l++ {
+			last[l].levels[l].next = node
+			last[l].levels[l].span = rank - lastRank[l]
+			last[l], lastRank[l] = node, rank
+			sl.levelCount[l]++
+		}
+		sl.maxLevel = max(sl.maxLevel, lvl)
+	}
+
+	// the last node of every level, or the head of an empty one, reaches past
+	// the end of the list
+	for l := range last {
+		last[l].levels[l].span = len(vals) + 1 - lastRank[l]
+	}
+	sl.length = len(vals)
+	return nil
+}
+
+// ensureComparator lets a zero SkipList, such as one allocated by
+// encoding/json for a nil pointer field, be decoded into when its element type
+// is an ordered basic kind.
+func (sl *SkipList[T]) ensureComparator() error {
+	if sl.comparator != nil {
+		return nil
+	}
+	comparator, ok := kindComparator[T]()
+	if !ok {
+		return ErrNoComparator
+	}
+	sl.comparator = comparator
+	sl.Clear()
+	return nil
+}
+
+func (sl *SkipList[T]) elementCodec() (Codec[T], error) {
+	return codecOrBuiltIn(sl.codec)
+}
+
+// codecOrBuiltIn returns codec, or the built-in codec of T if codec is nil.
+func codecOrBuiltIn[T any](codec Codec[T]) (Codec[T], error) {
+	if codec != nil {
+		return codec, nil
+	}
+	if builtIn, ok := kindCodecFor[T](); ok {
+		return builtIn, nil
+	}
+	return nil, ErrNoCodec
+}
+
+// kindComparator returns a comparator for element types whose kind is an
+// integer, float or string, matching cmp.Compare.
+func kindComparator[T any]() (Comparator[T], bool) {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}, true
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}, true
+	case reflect.String:
+		return func(a, b T) int {
+			return strings.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}, true
+	}
+	return nil, false
+}
+
+// kindCodec is the built-in codec for integer, float, string and []byte kinds.
+type kindCodec[T any] struct{}
+
+func kindCodecFor[T any]() (Codec[T], bool) {
+	t := reflect.TypeFor[T]()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return kindCodec[T]{}, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindCodec[T]{}, true
+		}
+	}
+	return nil, false
+}
+
+func (kindCodec[T]) Encode(val T) ([]byte, error) {
+	v := reflect.ValueOf(&val).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(nil, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(nil, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.BigEndian.AppendUint64(nil, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return []byte(v.String()), nil
+	default:
+		return bytes.Clone(v.Bytes()), nil
+	}
+}
+
+func (kindCodec[T]) Decode(data []byte) (T, error) {
+	var val T
+	v := reflect.ValueOf(&val).Elem()
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, n := binary.Varint(data)
+		if n != len(data) || v.OverflowInt(x) {
+			return val, fmt.Errorf("invalid %s", v.Type())
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, n := binary.Uvarint(data)
+		if n != len(data) || v.OverflowUint(x) {
+			return val, fmt.Errorf("invalid %s", v.Type())
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		if len(data) != 8 {
+			return val, fmt.Errorf("invalid %s", v.Type())
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+	case reflect.String:
+		v.SetString(string(data))
+	default:
+		v.SetBytes(bytes.Clone(data))
+	}
+	return val, nil
+}
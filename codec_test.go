@@ -0,0 +1,264 @@
+package skiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Codec helpers
+// ------------------------------------------------------------
+
+// accountCodec encodes an account as its balance as a varint followed by its id.
+type accountCodec struct{}
+
+func (accountCodec) Encode(a account) ([]byte, error) {
+	return append(binary.AppendVarint(nil, int64(a.balance)), a.id...), nil
+}
+
+func (accountCodec) Decode(data []byte) (account, error) {
+	balance, n := binary.Varint(data)
+	if n <= 0 {
+		return account{}, fmt.Errorf("short account")
+	}
+	return account{id: string(data[n:]), balance: int(balance)}, nil
+}
+
+// ------------------------------------------------------------
+// Codec tests
+// ------------------------------------------------------------
+
+func TestMarshalBinary_RoundTrip(t *testing.T) {
+	sl := NewSkipList[int]()
+	for _, v := range []int{-300, -1, 0, 7, 42, 1 << 40} {
+		sl.Add(v)
+	}
+
+	data, err := sl.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned %v", err)
+	}
+	decoded := NewSkipList[int]()
+	decoded.Add(5) // replaced by the decoded contents
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned %v", err)
+	}
+
+	assertOrder(t, decoded, []int{-300, -1, 0, 7, 42, 1 << 40})
+	assertRanksConsistent(t, decoded)
+	if decoded.Len() != 6 {
+		t.Fatalf("expected Len 6, got %d", decoded.Len())
+	}
+}
+
+func TestMarshalBinary_BuiltInKinds(t *testing.T) {
+	strs := NewSkipList[string]()
+	for _, s := range []string{"", "apple", "banana"} {
+		strs.Add(s)
+	}
+	data, err := strs.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned %v", err)
+	}
+	decodedStrs := NewSkipList[string]()
+	if err := decodedStrs.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned %v", err)
+	}
+	if got := decodedStrs.Len(); got != 3 || !decodedStrs.Contains("") || !decodedStrs.Contains("banana") {
+		t.Fatalf("string round trip lost elements, Len %d", got)
+	}
+
+	floats := NewSkipList[float64]()
+	floats.Add(-1.5)
+	floats.Add(3.25)
+	data, err = floats.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned %v", err)
+	}
+	decodedFloats := NewSkipList[float64]()
+	if err := decodedFloats.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned %v", err)
+	}
+	if first, _ := decodedFloats.SearchByRank(1); first.Value() != -1.5 {
+		t.Fatalf("expected -1.5 first, got %v", first.Value())
+	}
+}
+
+func TestMarshalBinary_CustomCodec(t *testing.T) {
+	sl := NewComparableSkipList[account]()
+	if _, err := sl.MarshalBinary(); !errors.Is(err, ErrNoCodec) {
+		t.Fatalf("expected ErrNoCodec without a codec, got %v", err)
+	}
+
+	sl.UseCodec(accountCodec{})
+	sl.Add(account{id: "bob", balance: 20})
+	sl.Add(account{id: "alice", balance: -10})
+	data, err := sl.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned %v", err)
+	}
+
+	decoded := NewComparableSkipList[account]()
+	decoded.UseCodec(accountCodec{})
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned %v", err)
+	}
+	if node, found := decoded.SearchByRank(1); !found || node.Value() != (account{id: "alice", balance: -10}) {
+		t.Fatalf("expected alice first, got %v", node)
+	}
+}
+
+func TestMarshalJSON_RoundTrip(t *testing.T) {
+	sl := NewSkipList[string]()
+	for _, s := range []string{"pear", "apple", "fig"} {
+		sl.Add(s)
+	}
+
+	data, err := json.Marshal(sl)
+	if err != nil {
+		t.Fatalf("json.Marshal returned %v", err)
+	}
+	if string(data) != `["apple","fig","pear"]` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	decoded := NewSkipList[string]()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("json.Unmarshal returned %v", err)
+	}
+	if got, want := decoded.Len(), 3; got != want || !decoded.Contains("fig") {
+		t.Fatalf("JSON round trip lost elements, Len %d", got)
+	}
+}
+
+func TestUnmarshalJSON_ZeroValueField(t *testing.T) {
+	var doc struct {
+		Scores *SkipList[int] `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(`{"scores":[1,5,9]}`), &doc); err != nil {
+		t.Fatalf("json.Unmarshal returned %v", err)
+	}
+
+	doc.Scores.Add(3)
+	assertOrder(t, doc.Scores, []int{1, 3, 5, 9})
+	if rank, _ := doc.Scores.GetRank(9); rank != 4 {
+		t.Fatalf("expected rank 4, got %d", rank)
+	}
+}
+
+func TestUnmarshalJSON_NullLeavesListUnchanged(t *testing.T) {
+	sl := NewSkipList[int]()
+	for _, v := range []int{4, 2, 8} {
+		sl.Add(v)
+	}
+
+	if err := json.Unmarshal([]byte("null"), sl); err != nil {
+		t.Fatalf("json.Unmarshal(null) returned %v", err)
+	}
+	assertOrder(t, sl, []int{2, 4, 8})
+
+	doc := struct {
+		Scores SkipList[int] `json:"scores"`
+	}{}
+	if err := json.Unmarshal([]byte(`{"scores":null}`), &doc); err != nil {
+		t.Fatalf("json.Unmarshal returned %v", err)
+	}
+	if doc.Scores.Len() != 0 {
+		t.Fatalf("null field decoded to %d elements", doc.Scores.Len())
+	}
+}
+
+func TestGob_RoundTrip(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 0; i < 100; i++ {
+		sl.Add(i * 2)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(sl); err != nil {
+		t.Fatalf("gob encode returned %v", err)
+	}
+	decoded := NewSkipList[int]()
+	if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+		t.Fatalf("gob decode returned %v", err)
+	}
+
+	if got, want := collectLevelValues(decoded)[0], collectLevelValues(sl)[0]; !slicesEqual(got, want) {
+		t.Fatalf("gob round trip mismatch: got %v", got)
+	}
+	assertRanksConsistent(t, decoded)
+}
+
+func TestUnmarshal_RejectsUnsortedInput(t *testing.T) {
+	sl := NewSkipList[int]()
+	sl.Add(1)
+	sl.Add(2)
+
+	for _, input := range []string{`[1,3,2]`, `[1,2,2]`} {
+		if err := json.Unmarshal([]byte(input), sl); !errors.Is(err, ErrNotSorted) {
+			t.Fatalf("%s: expected ErrNotSorted, got %v", input, err)
+		}
+	}
+	assertOrder(t, sl, []int{1, 2})
+
+	bad := []byte{binaryVersion, 2, 1, 4, 1, 2} // 2 then 1
+	if err := sl.UnmarshalBinary(bad); !errors.Is(err, ErrNotSorted) {
+		t.Fatalf("expected ErrNotSorted, got %v", err)
+	}
+	if sl.Len() != 2 {
+		t.Fatalf("a rejected decode should leave the list unchanged, Len %d", sl.Len())
+	}
+}
+
+func TestUnmarshalBinary_RejectsCorruptInput(t *testing.T) {
+	sl := NewSkipList[int]()
+	for _, data := range [][]byte{
+		nil,
+		{9, 0},                      // unknown version
+		{binaryVersion, 3, 1, 2},    // truncated
+		{binaryVersion, 1, 1, 2, 0}, // trailing bytes
+	} {
+		if err := sl.UnmarshalBinary(data); err == nil {
+			t.Fatalf("expected an error for %v", data)
+		}
+	}
+}
+
+func TestBuildFromSorted_Shape(t *testing.T) {
+	vals := make([]int, 5000)
+	for i := range vals {
+		vals[i] = i * 3
+	}
+
+	sl := NewSkipList[int]()
+	if err := sl.buildFromSorted(vals); err != nil {
+		t.Fatalf("buildFromSorted returned %v", err)
+	}
+	assertRanksConsistent(t, sl)
+
+	// levelCount and maxLevel must match the towers that were built
+	for level := 0; level <= MaxLevelCap; level++ {
+		count := 0
+		for n := sl.head.levels[level].next; n != nil; n = n.levels[level].next {
+			count++
+		}
+		if count != sl.levelCount[level] {
+			t.Fatalf("level %d has %d nodes, levelCount says %d", level, count, sl.levelCount[level])
+		}
+		if count > 0 && level > sl.maxLevel {
+			t.Fatalf("level %d is populated above maxLevel %d", level, sl.maxLevel)
+		}
+	}
+
+	sl.Add(1)
+	sl.Delete(3000)
+	if rank, _ := sl.GetRank(2997); rank != 1001 {
+		t.Fatalf("expected rank 1001 after mutating, got %d", rank)
+	}
+	assertRanksConsistent(t, sl)
+}
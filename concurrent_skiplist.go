@@ -0,0 +1,277 @@
+package skiplist
+
+import (
+	"cmp"
+	"sync/atomic"
+)
+
+// ConcurrentSkipList is a lock-free skip list set, following the design of
+// Herlihy and Shavit's LockFreeSkipList and Java's ConcurrentSkipListSet.
+//
+// Every link is an atomic pointer to an immutable (next, marked) pair, so a
+// node's successor and its deletion mark change together in a single
+// compare-and-swap. Delete marks a node's links from the top level down
+// (logical deletion) and later traversals unlink marked nodes (physical
+// deletion). Add, Delete, Contains, SearchByValue and GetLowerBound are
+// linearizable and never block.
+//
+// Rank spans cannot be kept exact without locking, so this list has no rank
+// APIs (SearchByRank, GetRank and their reverse variants). Len is maintained
+// separately from the links and is only exact when no writes are in flight.
+// Range is weakly consistent, see its documentation.
+type ConcurrentSkipList[T any] struct {
+	head       *concurrentNode[T]
+	length     atomic.Int64
+	comparator Comparator[T]
+}
+
+type concurrentNode[T any] struct {
+	val  T
+	next []atomic.Pointer[concurrentLink[T]]
+}
+
+// concurrentLink is an immutable link. Links are compared by identity in CAS
+// operations, so changing either field means installing a new link.
+type concurrentLink[T any] struct {
+	node   *concurrentNode[T]
+	marked bool
+}
+
+// NewConcurrentSkipList creates a lock-free skip list for ordered types.
+func NewConcurrentSkipList[T cmp.Ordered]() *ConcurrentSkipList[T] {
+	return newConcurrentSkipList(cmp.Compare[T])
+}
+
+// NewComparableConcurrentSkipList creates a lock-free skip list for types that
+// implement the Comparable interface.
+func NewComparableConcurrentSkipList[T Comparable[T]]() *ConcurrentSkipList[T] {
+	return newConcurrentSkipList(func(a, b T) int {
+		return a.Compare(b)
+	})
+}
+
+func newConcurrentSkipList[T any](comparator Comparator[T]) *ConcurrentSkipList[T] {
+	var zero T
+	return &ConcurrentSkipList[T]{
+		head:       newConcurrentNode(zero, MaxLevelCap+1, nil),
+		comparator: comparator,
+	}
+}
+
+// newConcurrentNode creates a node of the given height whose levels all point
+// to the matching entry of succs, or to nil if succs is nil.
+func newConcurrentNode[T any](val T, height int, succs []*concurrentNode[T]) *concurrentNode[T] {
+	node := &concurrentNode[T]{
+		val:  val,
+		next: make([]atomic.Pointer[concurrentLink[T]], height),
+	}
+	for i := range node.next {
+		link := &concurrentLink[T]{}
+		if succs != nil {
+			link.node = succs[i]
+		}
+		node.next[i].Store(link)
+	}
+	return node
+}
+
+// find locates the predecessor and successor of val at every level, unlinking
+// marked nodes on the way. preds[i].next[i] held links[i], an unmarked link to
+// succs[i], when it was read. It reports whether an unmarked node equal to val
+// was found at level 0.
+func (sl *ConcurrentSkipList[T]) find(val T, preds, succs *[MaxLevelCap + 1]*concurrentNode[T], links *[MaxLevelCap + 1]*concurrentLink[T]) bool {
+retry:
+	for {
+		pred := sl.head
+		for level := MaxLevelCap; level >= 0; level-- {
+			link := pred.next[level].Load()
+			if link.marked {
+				// pred was deleted after we moved onto it; start over so it
+				// gets unlinked before we use it as a predecessor
+				continue retry
+			}
+
+			curr := link.node
+			for curr != nil {
+				succ := curr.next[level].Load()
+				if succ.marked {
+					unlinked := &concurrentLink[T]{node: succ.node}
+					if !pred.next[level].CompareAndSwap(link, unlinked) {
+						continue retry
+					}
+					link, curr = unlinked, succ.node
+					continue
+				}
+				if sl.comparator(curr.val, val) >= 0 {
+					break
+				}
+				pred, link, curr = curr, succ, succ.node
+			}
+
+			preds[level], succs[level], links[level] = pred, curr, link
+		}
+		return succs[0] != nil && sl.comparator(succs[0].val, val) == 0
+	}
+}
+
+// Add inserts val and reports whether it was added. Adding an existing value
+// is a no-op and returns false.
+func (sl *ConcurrentSkipList[T]) Add(val T) bool {
+	var preds, succs [MaxLevelCap + 1]*concurrentNode[T]
+	var links [MaxLevelCap + 1]*concurrentLink[T]
+	top := randomLevel()
+
+	for {
+		if sl.find(val, &preds, &succs, &links) {
+			return false
+		}
+
+		node := newConcurrentNode(val, top+1, succs[:top+1])
+		// the CAS at level 0 is the linearization point; from here on the
+		// node is in the set and the upper levels are only shortcuts
+		if !preds[0].next[0].CompareAndSwap(links[0], &concurrentLink[T]{node: node}) {
+			continue
+		}
+		sl.length.Add(1)
+
+		for level := 1; level <= top; level++ {
+			for {
+				own := node.next[level].Load()
+				if own.marked {
+					// deleted while we were linking it; leave the rest to find
+					return true
+				}
+				if own.node != succs[level] &&
+					!node.next[level].CompareAndSwap(own, &concurrentLink[T]{node: succs[level]}) {
+					continue
+				}
+				if preds[level].next[level].CompareAndSwap(links[level], &concurrentLink[T]{node: node}) {
+					break
+				}
+				sl.find(val, &preds, &succs, &links)
+			}
+		}
+		return true
+	}
+}
+
+// Delete removes val and reports whether this call removed it. When several
+// goroutines delete the same value concurrently, exactly one of them succeeds.
+func (sl *ConcurrentSkipList[T]) Delete(val T) bool {
+	var preds, succs [MaxLevelCap + 1]*concurrentNode[T]
+	var links [MaxLevelCap + 1]*concurrentLink[T]
+
+	if !sl.find(val, &preds, &succs, &links) {
+		return false
+	}
+
+	victim := succs[0]
+	for level := len(victim.next) - 1; level >= 1; level-- {
+		markLink(&victim.next[level])
+	}
+
+	// marking level 0 is the linearization point
+	for {
+		link := victim.next[0].Load()
+		if link.marked {
+			return false
+		}
+		if victim.next[0].CompareAndSwap(link, &concurrentLink[T]{node: link.node, marked: true}) {
+			sl.length.Add(-1)
+			sl.find(val, &preds, &succs, &links)
+			return true
+		}
+	}
+}
+
+// markLink sets the deletion mark of a link, keeping its successor.
+func markLink[T any](p *atomic.Pointer[concurrentLink[T]]) {
+	for {
+		link := p.Load()
+		if link.marked || p.CompareAndSwap(link, &concurrentLink[T]{node: link.node, marked: true}) {
+			return
+		}
+	}
+}
+
+// Contains checks if val exists in the list. Lookups never write and never
+// restart: they step over marked nodes instead of unlinking them.
+func (sl *ConcurrentSkipList[T]) Contains(val T) bool {
+	_, found := sl.SearchByValue(val)
+	return found
+}
+
+// SearchByValue returns the stored element equal to val.
+func (sl *ConcurrentSkipList[T]) SearchByValue(val T) (T, bool) {
+	if node := sl.ceiling(val); node != nil && sl.comparator(node.val, val) == 0 {
+		return node.val, true
+	}
+	var zero T
+	return zero, false
+}
+
+// GetLowerBound returns the first element greater than or equal to val.
+func (sl *ConcurrentSkipList[T]) GetLowerBound(val T) (T, bool) {
+	if node := sl.ceiling(val); node != nil {
+		return node.val, true
+	}
+	var zero T
+	return zero, false
+}
+
+// ceiling returns the first unmarked node not less than val, stepping over
+// marked nodes instead of unlinking them.
+func (sl *ConcurrentSkipList[T]) ceiling(val T) *concurrentNode[T] {
+	pred := sl.head
+	var curr *concurrentNode[T]
+
+	for level := MaxLevelCap; level >= 0; level-- {
+		curr = pred.next[level].Load().node
+		for curr != nil {
+			succ := curr.next[level].Load()
+			if succ.marked {
+				curr = succ.node
+				continue
+			}
+			if sl.comparator(curr.val, val) >= 0 {
+				break
+			}
+			pred, curr = curr, succ.node
+		}
+	}
+	return curr
+}
+
+// Range iterates over the elements in ascending order. If fn returns false,
+// iteration stops.
+//
+// Iteration is weakly consistent, like the iterators of Java's concurrent
+// collections: it never fails, every element present for the whole iteration
+// is visited, and elements added or deleted during the iteration may or may
+// not be visited.
+func (sl *ConcurrentSkipList[T]) Range(fn func(val T) bool) {
+	for curr := sl.head.next[0].Load().node; curr != nil; {
+		succ := curr.next[0].Load()
+		if !succ.marked && !fn(curr.val) {
+			return
+		}
+		curr = succ.node
+	}
+}
+
+// Len returns the number of elements in the list. It is exact when no Add or
+// Delete is in progress and approximate otherwise.
+func (sl *ConcurrentSkipList[T]) Len() int {
+	// a Delete can account for a node before the Add that linked it does
+	return max(int(sl.length.Load()), 0)
+}
+
+// IsEmpty reports whether the list has no elements.
+func (sl *ConcurrentSkipList[T]) IsEmpty() bool {
+	empty := true
+	sl.Range(func(T) bool {
+		empty = false
+		return false
+	})
+	return empty
+}
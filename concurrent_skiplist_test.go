@@ -0,0 +1,242 @@
+package skiplist
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// These tests are meant to be run with -race as well:
+//
+//	go test -race -run Concurrent
+
+// ------------------------------------------------------------
+// ConcurrentSkipList helpers
+// ------------------------------------------------------------
+
+func collectConcurrent(sl *ConcurrentSkipList[int]) []int {
+	var vals []int
+	sl.Range(func(v int) bool {
+		vals = append(vals, v)
+		return true
+	})
+	return vals
+}
+
+// runParallel starts n goroutines running fn and waits for all of them.
+func runParallel(n int, fn func(g int)) {
+	var wg sync.WaitGroup
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			fn(g)
+		}(g)
+	}
+	wg.Wait()
+}
+
+// ------------------------------------------------------------
+// ConcurrentSkipList tests
+// ------------------------------------------------------------
+
+func TestConcurrentSkipList_Sequential(t *testing.T) {
+	sl := NewConcurrentSkipList[int]()
+	if !sl.IsEmpty() {
+		t.Fatal("new list should be empty")
+	}
+
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		if !sl.Add(v) {
+			t.Fatalf("Add(%d) should succeed", v)
+		}
+	}
+	if sl.Add(3) {
+		t.Fatal("adding a duplicate should report false")
+	}
+	if !sl.Delete(9) || sl.Delete(9) || sl.Delete(4) {
+		t.Fatal("Delete should succeed once for present values only")
+	}
+
+	if got := collectConcurrent(sl); !slicesEqual(got, []int{1, 3, 5, 7}) {
+		t.Fatalf("unexpected contents: %v", got)
+	}
+	if sl.Len() != 4 {
+		t.Fatalf("expected Len 4, got %d", sl.Len())
+	}
+	if v, ok := sl.GetLowerBound(4); !ok || v != 5 {
+		t.Fatalf("GetLowerBound(4): got %d (%v), want 5", v, ok)
+	}
+	if _, ok := sl.GetLowerBound(8); ok {
+		t.Fatal("GetLowerBound past the end should report false")
+	}
+	if !sl.Contains(1) || sl.Contains(9) {
+		t.Fatal("Contains disagrees with the contents")
+	}
+}
+
+func TestConcurrentSkipList_ParallelDisjointWriters(t *testing.T) {
+	sl := NewConcurrentSkipList[int]()
+	const goroutines, perGoroutine = 8, 2000
+
+	runParallel(goroutines, func(g int) {
+		for i := 0; i < perGoroutine; i++ {
+			v := i*goroutines + g
+			if !sl.Add(v) {
+				t.Errorf("Add(%d) of a new value failed", v)
+			}
+		}
+		// delete every other value this goroutine added
+		for i := 0; i < perGoroutine; i += 2 {
+			if v := i*goroutines + g; !sl.Delete(v) {
+				t.Errorf("Delete(%d) of a present value failed", v)
+			}
+		}
+	})
+
+	got := collectConcurrent(sl)
+	if len(got) != goroutines*perGoroutine/2 || sl.Len() != len(got) {
+		t.Fatalf("expected %d elements, Range saw %d and Len is %d", goroutines*perGoroutine/2, len(got), sl.Len())
+	}
+	for i, v := range got {
+		if (v/goroutines)%2 == 0 {
+			t.Fatalf("deleted value %d is still present", v)
+		}
+		if i > 0 && got[i-1] >= v {
+			t.Fatalf("Range out of order: %d after %d", v, got[i-1])
+		}
+	}
+}
+
+func TestConcurrentSkipList_ContendedAddAndDelete(t *testing.T) {
+	sl := NewConcurrentSkipList[int]()
+	const goroutines, values = 8, 500
+
+	// every goroutine adds the same values; each must be added exactly once
+	var added atomic.Int64
+	runParallel(goroutines, func(int) {
+		for v := 0; v < values; v++ {
+			if sl.Add(v) {
+				added.Add(1)
+			}
+		}
+	})
+	if added.Load() != values || sl.Len() != values {
+		t.Fatalf("expected %d successful adds, got %d (Len %d)", values, added.Load(), sl.Len())
+	}
+
+	var deleted atomic.Int64
+	runParallel(goroutines, func(int) {
+		for v := 0; v < values; v++ {
+			if sl.Delete(v) {
+				deleted.Add(1)
+			}
+		}
+	})
+	if deleted.Load() != values || !sl.IsEmpty() || sl.Len() != 0 {
+		t.Fatalf("expected %d successful deletes, got %d (Len %d)", values, deleted.Load(), sl.Len())
+	}
+}
+
+func TestConcurrentSkipList_ReadersDuringChurn(t *testing.T) {
+	sl := NewConcurrentSkipList[int]()
+	const stable = 200
+
+	// even values are never touched by the writers
+	for v := 0; v < 2*stable; v += 2 {
+		sl.Add(v)
+	}
+
+	var stop atomic.Bool
+	var writers sync.WaitGroup
+	for g := 0; g < runtime.GOMAXPROCS(0); g++ {
+		writers.Add(1)
+		go func(seed int64) {
+			defer writers.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for !stop.Load() {
+				v := 2*rng.Intn(stable) + 1
+				if rng.Intn(2) == 0 {
+					sl.Add(v)
+				} else {
+					sl.Delete(v)
+				}
+			}
+		}(int64(g))
+	}
+
+	runParallel(4, func(int) {
+		for round := 0; round < 50; round++ {
+			seen, prev := 0, -1
+			sl.Range(func(v int) bool {
+				if v <= prev {
+					t.Errorf("Range out of order: %d after %d", v, prev)
+				}
+				if v%2 == 0 {
+					seen++
+				}
+				prev = v
+				return true
+			})
+			if seen != stable {
+				t.Errorf("Range saw %d of the %d untouched values", seen, stable)
+			}
+			for v := 0; v < 2*stable; v += 2 {
+				if !sl.Contains(v) {
+					t.Errorf("Contains(%d) missed an untouched value", v)
+				}
+			}
+		}
+	})
+
+	stop.Store(true)
+	writers.Wait()
+
+	got := collectConcurrent(sl)
+	if sl.Len() != len(got) {
+		t.Fatalf("Len %d disagrees with Range (%d) once writers are done", sl.Len(), len(got))
+	}
+}
+
+func TestConcurrentSkipList_MatchesSequentialModel(t *testing.T) {
+	sl := NewConcurrentSkipList[int]()
+	const goroutines, ops = 8, 3000
+
+	// each goroutine owns the values congruent to g, so the final contents are
+	// fully determined by each goroutine's own operations
+	models := make([]map[int]bool, goroutines)
+	runParallel(goroutines, func(g int) {
+		rng := rand.New(rand.NewSource(int64(g)))
+		model := map[int]bool{}
+		for i := 0; i < ops; i++ {
+			v := rng.Intn(200)*goroutines + g
+			if rng.Intn(3) == 0 {
+				if sl.Delete(v) != model[v] {
+					t.Errorf("Delete(%d) disagrees with the model", v)
+				}
+				delete(model, v)
+			} else {
+				if sl.Add(v) == model[v] {
+					t.Errorf("Add(%d) disagrees with the model", v)
+				}
+				model[v] = true
+			}
+		}
+		models[g] = model
+	})
+
+	want := 0
+	for _, model := range models {
+		want += len(model)
+		for v := range model {
+			if !sl.Contains(v) {
+				t.Fatalf("value %d should be present", v)
+			}
+		}
+	}
+	if got := collectConcurrent(sl); len(got) != want {
+		t.Fatalf("expected %d values, got %d", want, len(got))
+	}
+}
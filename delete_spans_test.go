@@ -93,14 +93,14 @@ func TestDeleteSpans_DeleteFirstNode(t *testing.T) {
 	// At level 0: head -> 20 (skip count 1)
 	// At level 1: head -> 20 (skip count 1)
 	// At level 2: head -> 20 (skip count 1)
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 1 {
-		t.Fatalf("head skip count at level 1 should be 1, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 1 {
+		t.Fatalf("head skip count at level 1 should be 1, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 1 {
-		t.Fatalf("head skip count at level 2 should be 1, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 1 {
+		t.Fatalf("head skip count at level 2 should be 1, got %d", s.head.levels[2].span)
 	}
 
 	// Node 20 should remain unchanged
@@ -164,14 +164,14 @@ func TestDeleteSpans_HeadSpansAfterDeletion(t *testing.T) {
 	// At level 0: head -> 10 (skip count 1)
 	// At level 1: head -> 20 (skip count 2)
 	// At level 2: head -> 30 (skip count 3)
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 2 {
-		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 2 {
+		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 3 {
-		t.Fatalf("head skip count at level 2 should be 3, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 3 {
+		t.Fatalf("head skip count at level 2 should be 3, got %d", s.head.levels[2].span)
 	}
 
 	// Delete node 20
@@ -181,14 +181,14 @@ func TestDeleteSpans_HeadSpansAfterDeletion(t *testing.T) {
 	// At level 0: head -> 10 (skip count 1)
 	// At level 1: head -> 30 (skip count 1, since 10 is at level 0)
 	// At level 2: head -> 30 (skip count 2, skipping 10)
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 2 {
-		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 2 {
+		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 2 {
-		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 2 {
+		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.levels[2].span)
 	}
 }
 
@@ -242,8 +242,8 @@ func TestDeleteSpans_AllLevelsUpdated(t *testing.T) {
 	// Head spans at levels above maxLevel should be decremented
 	// maxLevel is now 2 (from node 10), so levels 3+ should have one less skip (including the right sentinel)
 	for level := 3; level <= MaxLevelCap; level++ {
-		if s.head.skips[level] != 4 {
-			t.Fatalf("head skip count at level %d should be 4, got %d", level, s.head.skips[level])
+		if s.head.levels[level].span != 4 {
+			t.Fatalf("head skip count at level %d should be 4, got %d", level, s.head.levels[level].span)
 		}
 	}
 }
@@ -288,12 +288,12 @@ func TestDeleteSpans_VerifyAllNodes(t *testing.T) {
 	node20 := findNode(s, 20)
 	node30 := findNode(s, 30)
 
-	initial10Spans := make([]int, len(node10.skips))
-	initial20Spans := make([]int, len(node20.skips))
-	initial30Spans := make([]int, len(node30.skips))
-	copy(initial10Spans, node10.skips)
-	copy(initial20Spans, node20.skips)
-	copy(initial30Spans, node30.skips)
+	initial10Spans := make([]int, len(node10.levels))
+	initial20Spans := make([]int, len(node20.levels))
+	initial30Spans := make([]int, len(node30.levels))
+	copy(initial10Spans, spansOf(node10))
+	copy(initial20Spans, spansOf(node20))
+	copy(initial30Spans, spansOf(node30))
 
 	// Delete node 20
 	s.Delete(20)
@@ -306,11 +306,11 @@ func TestDeleteSpans_VerifyAllNodes(t *testing.T) {
 
 	// Node 10 should have updated spans
 	// The spans should reflect that 20 is no longer in the path
-	for level := 0; level < len(node10After.skips); level++ {
-		if level < len(node10After.forward) {
+	for level := 0; level < len(node10After.levels); level++ {
+		if level < len(node10After.levels) {
 			// Verify the span is positive and reasonable
-			if node10After.skips[level] <= 0 {
-				t.Fatalf("node 10 span at level %d should be positive, got %d", level, node10After.skips[level])
+			if node10After.levels[level].span <= 0 {
+				t.Fatalf("node 10 span at level %d should be positive, got %d", level, node10After.levels[level].span)
 			}
 		}
 	}
@@ -320,10 +320,10 @@ func TestDeleteSpans_VerifyAllNodes(t *testing.T) {
 	if node30After == nil {
 		t.Fatalf("node 30 not found after deletion")
 	}
-	if len(node30After.skips) != len(initial30Spans) {
-		t.Fatalf("node 30 should have same number of spans, got %d, expected %d", len(node30After.skips), len(initial30Spans))
+	if len(node30After.levels) != len(initial30Spans) {
+		t.Fatalf("node 30 should have same number of spans, got %d, expected %d", len(node30After.levels), len(initial30Spans))
 	}
-	for i, span := range node30After.skips {
+	for i, span := range spansOf(node30After) {
 		if span != initial30Spans[i] {
 			t.Fatalf("node 30 span at level %d should be unchanged (%d), got %d", i, initial30Spans[i], span)
 		}
@@ -389,25 +389,25 @@ func TestDeleteSpans_BasicIntegrity(t *testing.T) {
 
 	// Verify spans arrays still exist and have positive values
 	// (Even if they're not correctly updated, they should at least be valid)
-	for i, span := range node10After.skips {
+	for i, span := range spansOf(node10After) {
 		if span <= 0 {
 			t.Fatalf("node 10 span at level %d should be positive, got %d", i, span)
 		}
 	}
-	for i, span := range node30After.skips {
+	for i, span := range spansOf(node30After) {
 		if span <= 0 {
 			t.Fatalf("node 30 span at level %d should be positive, got %d", i, span)
 		}
 	}
 
 	// Verify forward pointers are still valid
-	for i := 0; i < len(node10After.forward); i++ {
-		if node10After.forward[i] == nil {
+	for i := 0; i < len(node10After.levels); i++ {
+		if node10After.levels[i].next == nil {
 			t.Fatalf("node 10 forward pointer at level %d should not be nil", i)
 		}
 	}
-	for i := 0; i < len(node30After.forward); i++ {
-		if node30After.forward[i] != nil {
+	for i := 0; i < len(node30After.levels); i++ {
+		if node30After.levels[i].next != nil {
 			t.Fatalf("node 30 forward pointer at level %d should be nil", i)
 		}
 	}
@@ -636,8 +636,8 @@ func TestDeleteSpans_DeleteThenAddSameValue(t *testing.T) {
 	checkSpans(t, s, 30, []int{1, 2, 2})
 	checkSpans(t, s, 40, []int{1})
 
-	if s.head.skips[3] != 2 {
-		t.Fatalf("head skip count at level 3 should be 2, got %d", s.head.skips[3])
+	if s.head.levels[3].span != 2 {
+		t.Fatalf("head skip count at level 3 should be 2, got %d", s.head.levels[3].span)
 	}
 }
 
@@ -746,14 +746,14 @@ func TestDeleteSpans_HeadSpansThroughOperations(t *testing.T) {
 	// l0: -INF --> 10 --> 20 --> 30 --> INF
 
 	// Verify initial head spans
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 2 {
-		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 2 {
+		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 3 {
-		t.Fatalf("head skip count at level 2 should be 3, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 3 {
+		t.Fatalf("head skip count at level 2 should be 3, got %d", s.head.levels[2].span)
 	}
 
 	// Delete 20
@@ -764,14 +764,14 @@ func TestDeleteSpans_HeadSpansThroughOperations(t *testing.T) {
 	// l0: -INF --> 10 --> 30 --> INF
 
 	// Verify head spans after deletion
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 2 {
-		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 2 {
+		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 2 {
-		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 2 {
+		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.levels[2].span)
 	}
 
 	// Add 25 at level 1
@@ -782,14 +782,14 @@ func TestDeleteSpans_HeadSpansThroughOperations(t *testing.T) {
 	// l0: -INF --> 10 --> 25 --> 30 --> INF
 
 	// Verify head spans after addition
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 2 {
-		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 2 {
+		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 3 {
-		t.Fatalf("head skip count at level 2 should be 3, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 3 {
+		t.Fatalf("head skip count at level 2 should be 3, got %d", s.head.levels[2].span)
 	}
 
 	// Delete 10 (first node)
@@ -800,14 +800,14 @@ func TestDeleteSpans_HeadSpansThroughOperations(t *testing.T) {
 	// l0: -INF --> 25 --> 30 --> INF
 
 	// Verify head spans after deleting first node
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
-	if s.head.skips[1] != 1 {
-		t.Fatalf("head skip count at level 1 should be 1, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 1 {
+		t.Fatalf("head skip count at level 1 should be 1, got %d", s.head.levels[1].span)
 	}
-	if s.head.skips[2] != 2 {
-		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 2 {
+		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.levels[2].span)
 	}
 }
 
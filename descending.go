@@ -0,0 +1,118 @@
+package skiplist
+
+// GetRevRank gets the rank (1-indexed position) of a value counted from the
+// largest element. Returns the rank and true if found, or -1 and false if not found.
+//
+// Time Complexity: O(log n) average
+func (sl *SkipList[T]) GetRevRank(item T) (int, bool) {
+	rank, found := sl.GetRank(item)
+	if !found {
+		return -1, false
+	}
+	return sl.length - rank + 1, true
+}
+
+// SearchByRevRank searches for the element at a given position (1-indexed)
+// counted from the largest element. Returns nil if rank is out of bounds.
+//
+// Time Complexity: O(log n) average
+func (sl *SkipList[T]) SearchByRevRank(rank int) (*Node[T], bool) {
+	if rank < 1 || rank > sl.length {
+		return nil, false
+	}
+	return sl.SearchByRank(sl.length - rank + 1)
+}
+
+// DescendingView is a read-only view of a skip list in descending order. It
+// shares the list's nodes, so it costs nothing to create and always reflects
+// the list's current contents.
+type DescendingView[T any] struct {
+	sl *SkipList[T]
+}
+
+// Descending returns a view of the list ordered from the largest element to the
+// smallest. Rank and bound queries on the view use descending semantics.
+func (sl *SkipList[T]) Descending() DescendingView[T] {
+	return DescendingView[T]{sl: sl}
+}
+
+// Len returns the number of elements in the list.
+func (d DescendingView[T]) Len() int {
+	return d.sl.Len()
+}
+
+// IsEmpty returns true if the list contains no elements.
+func (d DescendingView[T]) IsEmpty() bool {
+	return d.sl.IsEmpty()
+}
+
+// Contains checks if a value exists in the list.
+func (d DescendingView[T]) Contains(val T) bool {
+	return d.sl.Contains(val)
+}
+
+// SearchByValue searches for a value and returns the node and a boolean indicating if found.
+func (d DescendingView[T]) SearchByValue(val T) (*Node[T], bool) {
+	return d.sl.SearchByValue(val)
+}
+
+// SearchByRank returns the element at the given 1-indexed position counted
+// from the largest element.
+func (d DescendingView[T]) SearchByRank(rank int) (*Node[T], bool) {
+	return d.sl.SearchByRevRank(rank)
+}
+
+// GetRank returns the 1-indexed position of item counted from the largest element.
+func (d DescendingView[T]) GetRank(item T) (int, bool) {
+	return d.sl.GetRevRank(item)
+}
+
+// GetLowerBound returns the first element in descending order that is less than
+// or equal to val, i.e. the largest element not greater than val.
+//
+// Time Complexity: O(log n) average
+func (d DescendingView[T]) GetLowerBound(val T) (*Node[T], bool) {
+	node := d.sl.lastBefore(func(v T) bool {
+		return d.sl.comparator(v, val) <= 0
+	})
+	return node, node != nil
+}
+
+// Range iterates over all elements in descending order.
+// The function fn is called for each element. If fn returns false, iteration stops.
+//
+// Nodes only link forward, so Range reverses the list level by level: the
+// nodes between two neighbours on a level are found by walking the level below
+// once, and visited back to front. Every link is followed once, so iterating
+// the whole list costs O(n) on average and makes no comparisons.
+func (d DescendingView[T]) Range(fn func(val T) bool) {
+	if d.sl.head == nil {
+		return
+	}
+	var stack []*Node[T]
+	d.between(d.sl.head, nil, d.sl.maxLevel, &stack, fn)
+}
+
+// between calls fn in descending order for the nodes strictly between from
+// and to, which are neighbours at level lvl+1, and reports whether fn asked to
+// continue. The nodes linked between them at lvl are pushed on stack while the
+// gaps between them are reversed one level down.
+func (d DescendingView[T]) between(from, to *Node[T], lvl int, stack *[]*Node[T], fn func(val T) bool) bool {
+	base := len(*stack)
+	for node := from.levels[lvl].next; node != to; node = node.levels[lvl].next {
+		*stack = append(*stack, node)
+	}
+	defer func() { *stack = (*stack)[:base] }()
+
+	for i := len(*stack) - 1; i >= base; i-- {
+		node := (*stack)[i]
+		if lvl > 0 && !d.between(node, to, lvl-1, stack, fn) {
+			return false
+		}
+		if !fn(node.val) {
+			return false
+		}
+		to = node
+	}
+	return lvl == 0 || d.between(from, to, lvl-1, stack, fn)
+}
@@ -0,0 +1,156 @@
+package skiplist
+
+import (
+	"cmp"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Reverse rank tests
+// ------------------------------------------------------------
+
+func TestGetRevRank(t *testing.T) {
+	sl := NewSkipList[int]()
+	for _, v := range []int{30, 10, 50, 20, 40} {
+		sl.Add(v)
+	}
+
+	expected := map[int]int{50: 1, 40: 2, 30: 3, 20: 4, 10: 5}
+	for val, want := range expected {
+		if got, found := sl.GetRevRank(val); !found || got != want {
+			t.Fatalf("GetRevRank(%d): got %d (%v), want %d", val, got, found, want)
+		}
+		if node, found := sl.SearchByRevRank(want); !found || node.val != val {
+			t.Fatalf("SearchByRevRank(%d): got %v (%v), want %d", want, node, found, val)
+		}
+	}
+
+	if rank, found := sl.GetRevRank(35); found || rank != -1 {
+		t.Fatalf("GetRevRank of missing value should return (-1, false), got (%d, %v)", rank, found)
+	}
+	for _, rank := range []int{0, 6, -1} {
+		if _, found := sl.SearchByRevRank(rank); found {
+			t.Fatalf("SearchByRevRank(%d) should be out of bounds", rank)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// Descending view tests
+// ------------------------------------------------------------
+
+func TestDescendingView_Range(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 1; i <= 100; i++ {
+		sl.Add(i)
+	}
+
+	var got []int
+	sl.Descending().Range(func(v int) bool {
+		got = append(got, v)
+		return len(got) < 5
+	})
+	if !slicesEqual(got, []int{100, 99, 98, 97, 96}) {
+		t.Fatalf("unexpected descending prefix %v", got)
+	}
+
+	count := 0
+	prev := 101
+	sl.Descending().Range(func(v int) bool {
+		if v >= prev {
+			t.Fatalf("descending order violated: %d after %d", v, prev)
+		}
+		prev = v
+		count++
+		return true
+	})
+	if count != 100 {
+		t.Fatalf("expected 100 elements, got %d", count)
+	}
+}
+
+func TestDescendingView_RangeMakesNoComparisons(t *testing.T) {
+	compares := 0
+	sl := newSkipList(func(a, b int) int {
+		compares++
+		return cmp.Compare(a, b)
+	})
+	for _, n := range []int{1, 2, 3, 1000} {
+		sl.Clear()
+		for i := range n {
+			sl.Add(i * 2)
+		}
+
+		compares = 0
+		var got []int
+		sl.Descending().Range(func(v int) bool {
+			got = append(got, v)
+			return true
+		})
+		if len(got) != n {
+			t.Fatalf("n=%d: Range visited %d elements", n, len(got))
+		}
+		for i, v := range got {
+			if v != (n-1-i)*2 {
+				t.Fatalf("n=%d: element %d is %d, want %d", n, i, v, (n-1-i)*2)
+			}
+		}
+		if compares != 0 {
+			t.Fatalf("n=%d: Range made %d comparisons, want none", n, compares)
+		}
+	}
+}
+
+func TestDescendingView_EmptyList(t *testing.T) {
+	view := NewSkipList[int]().Descending()
+
+	view.Range(func(int) bool {
+		t.Fatal("Range on empty view should not call fn")
+		return false
+	})
+	if _, found := view.GetLowerBound(10); found {
+		t.Fatal("GetLowerBound on empty view should not find anything")
+	}
+	if _, found := view.SearchByRank(1); found {
+		t.Fatal("SearchByRank on empty view should not find anything")
+	}
+}
+
+func TestDescendingView_Queries(t *testing.T) {
+	sl := NewSkipList[int]()
+	for _, v := range []int{10, 20, 30, 40} {
+		sl.Add(v)
+	}
+	view := sl.Descending()
+
+	if node, found := view.SearchByRank(1); !found || node.val != 40 {
+		t.Fatalf("SearchByRank(1) should be 40, got %v", node)
+	}
+	if rank, _ := view.GetRank(10); rank != 4 {
+		t.Fatalf("GetRank(10) should be 4, got %d", rank)
+	}
+
+	tests := []struct {
+		val   int
+		want  int
+		found bool
+	}{
+		{45, 40, true},
+		{40, 40, true},
+		{25, 20, true},
+		{10, 10, true},
+		{5, 0, false},
+	}
+	for _, tc := range tests {
+		node, found := view.GetLowerBound(tc.val)
+		if found != tc.found || (found && node.val != tc.want) {
+			t.Fatalf("GetLowerBound(%d): got %v (%v), want %d (%v)", tc.val, node, found, tc.want, tc.found)
+		}
+	}
+
+	// the view reflects later changes to the list
+	sl.Add(50)
+	if node, _ := view.SearchByRank(1); node.val != 50 {
+		t.Fatalf("view should see newly added 50, got %v", node.val)
+	}
+}
@@ -0,0 +1,180 @@
+package skiplist
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// String returns the node's value formatted with fmt.
+func (n *Node[T]) String() string {
+	if n == nil {
+		return "<nil>"
+	}
+	return fmt.Sprint(n.val)
+}
+
+// String returns the elements of the list in order, formatted like a slice.
+func (sl *SkipList[T]) String() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for node := sl.firstNode(); node != nil; node = node.levels[0].next {
+		if b.Len() > 1 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprint(&b, node.val)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// Dump writes a diagram of the list to w, with one row per level from the top
+// down. Every element has its own column, and each arrow is labelled with its
+// span:
+//
+//	L2  head --3----------------------> 30 --1--> nil
+//	L1  head --1--> 10 --2------------> 30 --1--> nil
+//	L0  head --1--> 10 --1--> 20 --1--> 30 --1--> nil
+//
+// The diagram is as wide as the list is long, so it is meant for the small
+// lists built in tests. Columns follow level 0 and spans are only printed, so
+// a list whose spans are broken is drawn as it is rather than rejected.
+func (sl *SkipList[T]) Dump(w io.Writer) error {
+	if sl.head == nil {
+		_, err := io.WriteString(w, "L0  head --1--> nil\n")
+		return err
+	}
+
+	// columns are placed by walking level 0, not by adding up spans, so a
+	// list with broken spans can still be drawn; column i+1 holds the node of
+	// rank i+1, column 0 is the head and the last column is nil
+	var labels []string
+	column := map[*Node[T]]int{}
+	for node := sl.head.levels[0].next; node != nil; node = node.levels[0].next {
+		if _, seen := column[node]; seen {
+			break
+		}
+		labels = append(labels, fmt.Sprint(node.val))
+		column[node] = len(labels)
+	}
+
+	gap := len(strconv.Itoa(sl.length+1)) + 7
+	starts := make([]int, len(labels)+2)
+	x := len("head") + gap
+	for i, label := range labels {
+		starts[i+1] = x
+		x += len(label) + gap
+	}
+	starts[len(labels)+1] = x
+
+	top := min(sl.maxLevel, len(sl.head.levels)-1)
+	rowLabel := len(fmt.Sprintf("L%d", top)) + 2
+	var b strings.Builder
+	for lvl := top; lvl >= 0; lvl-- {
+		fmt.Fprintf(&b, "%-*s", rowLabel, fmt.Sprintf("L%d", lvl))
+		b.WriteString("head")
+		end := len("head")
+		// the step bound stops a row whose links form a cycle
+		for node, steps := sl.head, 0; steps <= len(labels); steps++ {
+			l := node.levels[lvl]
+			target := len(labels) + 1
+			if l.next != nil {
+				// a node missing from level 0 gets no column
+				target = column[l.next]
+			}
+			arrow := dumpArrow(starts[target]-end, l.span)
+			if target == 0 {
+				arrow = dumpArrow(0, l.span)
+			}
+			b.WriteString(arrow)
+			if l.next == nil {
+				b.WriteString("nil")
+				break
+			}
+			label := fmt.Sprint(l.next.val)
+			b.WriteString(label)
+			end += len(arrow) + len(label)
+			node = l.next
+			if len(node.levels) <= lvl {
+				break
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dumpArrow draws an arrow labelled with span, such as " --2--> ", width
+// columns wide or as narrow as the label allows.
+func dumpArrow(width, span int) string {
+	label := strconv.Itoa(span)
+	return " --" + label + strings.Repeat("-", max(width-len(label)-5, 2)) + "> "
+}
+
+// WriteDOT writes the list to w in the Graphviz DOT language. Each node is a
+// record with one field per level, and each link is an edge labelled with its
+// span. Render it with, for example:
+//
+//	dot -Tsvg list.dot -o list.svg
+func (sl *SkipList[T]) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph skiplist {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=record];\n")
+	b.WriteString("\tnil [shape=plaintext];\n")
+
+	if sl.head == nil {
+		b.WriteString("\thead [label=\"<l0> |head\"];\n")
+		b.WriteString("\thead:l0 -> nil [label=\"1\"];\n")
+	} else {
+		ids := map[*Node[T]]string{sl.head: "head"}
+		rank := 0
+		for node := sl.head.levels[0].next; node != nil; node = node.levels[0].next {
+			rank++
+			ids[node] = "n" + strconv.Itoa(rank)
+		}
+
+		for node := sl.head; node != nil; node = node.levels[0].next {
+			height := len(node.levels)
+			label := "head"
+			if node == sl.head {
+				height = sl.maxLevel + 1
+			} else {
+				label = dotEscape(fmt.Sprint(node.val))
+			}
+			fmt.Fprintf(&b, "\t%s [label=\"", ids[node])
+			for lvl := height - 1; lvl >= 0; lvl-- {
+				fmt.Fprintf(&b, "<l%d> |", lvl)
+			}
+			fmt.Fprintf(&b, "%s\"];\n", label)
+
+			for lvl := 0; lvl < height; lvl++ {
+				l := node.levels[lvl]
+				to := "nil"
+				if l.next != nil {
+					to = fmt.Sprintf("%s:l%d", ids[l.next], lvl)
+				}
+				fmt.Fprintf(&b, "\t%s:l%d -> %s [label=\"%d\"];\n", ids[node], lvl, to, l.span)
+			}
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotEscape escapes the characters that are special in a record label.
+func dotEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`{}|<>"\ `, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package skiplist
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Dump and DOT tests
+// ------------------------------------------------------------
+
+func TestDump_AlignsColumnsAndLabelsSpans(t *testing.T) {
+	s := NewSkipList[int]()
+	s.InsertAtLevel(10, 1)
+	s.InsertAtLevel(20, 0)
+	s.InsertAtLevel(30, 2)
+
+	want := "" +
+		"L2  head --3----------------------> 30 --1--> nil\n" +
+		"L1  head --1--> 10 --2------------> 30 --1--> nil\n" +
+		"L0  head --1--> 10 --1--> 20 --1--> 30 --1--> nil\n"
+	if got := dumpString(s); got != want {
+		t.Fatalf("unexpected diagram:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := dumpString(NewSkipList[int]()); got != "L0  head --1--> nil\n" {
+		t.Fatalf("unexpected diagram of an empty list:\n%s", got)
+	}
+	var zero SkipList[int]
+	if got := dumpString(&zero); got != "L0  head --1--> nil\n" {
+		t.Fatalf("unexpected diagram of a zero list:\n%s", got)
+	}
+}
+
+func TestDump_WideSpansAndValues(t *testing.T) {
+	s := NewSkipList[int]()
+	s.InsertAtLevel(1, 1)
+	for i := 2; i <= 11; i++ {
+		s.InsertAtLevel(i, 0)
+	}
+	s.InsertAtLevel(1000, 1)
+
+	rows := strings.Split(strings.TrimSuffix(dumpString(s), "\n"), "\n")
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	// every row ends at the same nil column
+	if len(rows[0]) != len(rows[1]) {
+		t.Fatalf("rows are not aligned:\n%s\n%s", rows[0], rows[1])
+	}
+	if !strings.Contains(rows[0], " 1 --11--") || !strings.HasSuffix(rows[0], "> 1000 --1---> nil") {
+		t.Fatalf("unexpected top row %q", rows[0])
+	}
+	if i, j := strings.Index(rows[0], "1000"), strings.Index(rows[1], "1000"); i != j {
+		t.Fatalf("1000 is at column %d on L1 and %d on L0", i, j)
+	}
+}
+
+func TestDump_CorruptSpans(t *testing.T) {
+	for _, span := range []int{0, 2, 99, -1} {
+		s := NewSkipList[int]()
+		s.InsertAtLevel(10, 1)
+		s.InsertAtLevel(20, 0)
+		s.InsertAtLevel(30, 2)
+		s.head.levels[0].span = span
+
+		// the diagram still places every node and prints the recorded span
+		rows := strings.Split(dumpString(s), "\n")
+		want := fmt.Sprintf("L0  head --%d-", span)
+		if !strings.HasPrefix(rows[2], want) || !strings.HasSuffix(rows[2], "> 10 --1--> 20 --1--> 30 --1--> nil") {
+			t.Fatalf("span %d: unexpected L0 row %q", span, rows[2])
+		}
+	}
+
+	// a link into a cycle does not hang the dump
+	s := NewSkipList[int]()
+	s.InsertAtLevel(10, 1)
+	s.InsertAtLevel(20, 1)
+	findNode(s, 20).levels[1].next = findNode(s, 10)
+	dumpString(s)
+}
+
+func TestWriteDOT(t *testing.T) {
+	s := NewSkipList[string]()
+	s.InsertAtLevel("a|b", 1)
+	s.InsertAtLevel("c", 0)
+
+	var b strings.Builder
+	if err := s.WriteDOT(&b); err != nil {
+		t.Fatalf("WriteDOT returned %v", err)
+	}
+	dot := b.String()
+	for _, want := range []string{
+		"digraph skiplist {",
+		`head [label="<l1> |<l0> |head"];`,
+		`n1 [label="<l1> |<l0> |a\|b"];`,
+		`head:l1 -> n1:l1 [label="1"];`,
+		`n1:l0 -> n2:l0 [label="1"];`,
+		`n1:l1 -> nil [label="2"];`,
+		`n2:l0 -> nil [label="1"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("DOT output is missing %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	s := NewSkipList[int]()
+	for _, v := range []int{20, 5, 10} {
+		s.Add(v)
+	}
+	if got := fmt.Sprint(s); got != "[5 10 20]" {
+		t.Fatalf("expected [5 10 20], got %s", got)
+	}
+	node, _ := s.SearchByRank(2)
+	if got := fmt.Sprint(node); got != "10" {
+		t.Fatalf("expected node to print as 10, got %s", got)
+	}
+	var missing *Node[int]
+	if got := fmt.Sprint(missing); got != "<nil>" {
+		t.Fatalf("expected a nil node to print as <nil>, got %s", got)
+	}
+	var zero SkipList[int]
+	if got := zero.String(); got != "[]" {
+		t.Fatalf("expected [], got %s", got)
+	}
+}
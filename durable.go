@@ -0,0 +1,385 @@
+package skiplist
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/anchor54/SkipList/internal/record"
+)
+
+// ErrCorruptLog is returned when a log record fails its checksum or has an
+// invalid length while more of the log follows it. A crash can only tear the
+// last record, so such a record means the log itself is damaged, and the
+// records after it must not be dropped.
+var ErrCorruptLog = record.ErrCorrupt
+
+// SyncPolicy selects when a DurableSkipList fsyncs its log.
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs after every mutation, so an acknowledged write
+	// survives a power failure.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval fsyncs on the first write after SyncInterval has elapsed
+	// since the last fsync. A power failure can lose the writes made since.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system. Writes survive a
+	// process crash but not a power failure.
+	SyncNever
+)
+
+// DurableOptions configures a DurableSkipList.
+type DurableOptions[T any] struct {
+	// Sync selects when the log is fsynced. The default is SyncAlways.
+	Sync SyncPolicy
+	// SyncInterval is the fsync interval used with SyncInterval.
+	SyncInterval time.Duration
+	// CheckpointEvery makes the list checkpoint automatically once the log
+	// holds this many records. Zero disables automatic checkpoints. A failed
+	// automatic checkpoint does not fail the write that triggered it; it is
+	// retried on the next write and reported by the next Sync.
+	CheckpointEvery int
+	// Codec encodes elements in the log and checkpoints. It is required for
+	// element types without a built-in codec.
+	Codec Codec[T]
+}
+
+// The log is a sequence of checksummed records. Each payload
+// is one op byte followed by the encoded element.
+const (
+	walOpAdd    = 1
+	walOpDelete = 2
+)
+
+// DurableSkipList is a skip list whose mutations are written to a log file
+// before they are applied, so its contents survive a crash.
+//
+// On open, the latest checkpoint is restored and the log is replayed on top of
+// it. A log whose tail was torn by a crash is truncated after its last intact
+// record, while a damaged record in the middle of the log makes the open fail
+// with ErrCorruptLog and leaves the log as it is. Checkpoint writes a full
+// snapshot and empties the log, keeping recovery time bounded.
+//
+// A DurableSkipList is safe for concurrent use. After a failed log write, every
+// later mutation returns the same error, since the log may no longer reflect
+// the list.
+type DurableSkipList[T any] struct {
+	mu       sync.RWMutex
+	list     *SkipList[T]
+	opts     DurableOptions[T]
+	path     string
+	log      *os.File
+	records  int
+	lastSync time.Time
+	err      error
+	// checkpointErr is the last automatic checkpoint failure not yet reported
+	// by Sync or Checkpoint
+	checkpointErr error
+}
+
+// OpenDurable opens or creates a durable skip list for ordered types. The log
+// is stored at path and checkpoints at path + ".snap".
+func OpenDurable[T cmp.Ordered](path string, opts DurableOptions[T]) (*DurableSkipList[T], error) {
+	return openDurable(NewSkipList[T](), path, opts)
+}
+
+// OpenComparableDurable opens or creates a durable skip list for types that
+// implement the Comparable interface.
+func OpenComparableDurable[T Comparable[T]](path string, opts DurableOptions[T]) (*DurableSkipList[T], error) {
+	return openDurable(NewComparableSkipList[T](), path, opts)
+}
+
+func openDurable[T any](list *SkipList[T], path string, opts DurableOptions[T]) (*DurableSkipList[T], error) {
+	if opts.Codec != nil {
+		list.UseCodec(opts.Codec)
+	}
+	if _, err := list.elementCodec(); err != nil {
+		return nil, err
+	}
+
+	d := &DurableSkipList[T]{list: list, opts: opts, path: path, lastSync: time.Now()}
+
+	if snap, err := os.Open(d.snapshotPath()); err == nil {
+		err = list.Restore(bufio.NewReader(snap))
+		snap.Close()
+		if err != nil {
+			return nil, fmt.Errorf("skiplist: restoring checkpoint: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.replay(log); err != nil {
+		log.Close()
+		return nil, err
+	}
+	d.log = log
+	return d, nil
+}
+
+// replay applies every record in log. A tail torn by a crash is truncated,
+// leaving the log positioned for appends. A damaged record in the middle of the
+// log is reported as ErrCorruptLog and the log is left untouched, since
+// truncating it would discard the intact records after it.
+func (d *DurableSkipList[T]) replay(log *os.File) error {
+	codec, _ := d.list.elementCodec()
+	rr := record.NewReader(log)
+
+	for {
+		payload, err := rr.Next()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("skiplist: replaying %s: %w", log.Name(), err)
+		}
+
+		val, err := codec.Decode(payload[1:])
+		if err != nil {
+			return fmt.Errorf("skiplist: log record at offset %d: %w", rr.Offset(), err)
+		}
+		switch payload[0] {
+		case walOpAdd:
+			d.list.Add(val)
+		case walOpDelete:
+			d.list.Delete(val)
+		default:
+			return fmt.Errorf("skiplist: log record at offset %d has unknown op %d", rr.Offset(), payload[0])
+		}
+		d.records++
+	}
+
+	if err := log.Truncate(rr.Offset()); err != nil {
+		return err
+	}
+	_, err := log.Seek(rr.Offset(), io.SeekStart)
+	return err
+}
+
+// Add logs and inserts val. Adding an existing value is a no-op and is not
+// logged. An error means val was neither logged nor inserted.
+func (d *DurableSkipList[T]) Add(val T) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.list.Contains(val) {
+		return d.err
+	}
+	if err := d.append(walOpAdd, val); err != nil {
+		return err
+	}
+	d.list.Add(val)
+	d.maybeCheckpoint()
+	return nil
+}
+
+// Delete logs and removes val. Deleting a missing value is a no-op and is not
+// logged. An error means val was neither logged nor removed.
+func (d *DurableSkipList[T]) Delete(val T) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.list.Contains(val) {
+		return d.err
+	}
+	if err := d.append(walOpDelete, val); err != nil {
+		return err
+	}
+	d.list.Delete(val)
+	d.maybeCheckpoint()
+	return nil
+}
+
+// append writes one record to the log and syncs it as the policy requires.
+func (d *DurableSkipList[T]) append(op byte, val T) error {
+	if d.err != nil {
+		return d.err
+	}
+	codec, _ := d.list.elementCodec()
+	data, err := codec.Encode(val)
+	if err != nil {
+		return err
+	}
+
+	rec := make([]byte, 0, record.HeaderSize+1+len(data))
+	rec = record.Append(rec, append([]byte{op}, data...))
+	if _, err := d.log.Write(rec); err != nil {
+		d.err = err
+		return err
+	}
+	d.records++
+
+	switch {
+	case d.opts.Sync == SyncAlways,
+		d.opts.Sync == SyncInterval && time.Since(d.lastSync) >= d.opts.SyncInterval:
+		return d.sync()
+	}
+	return nil
+}
+
+func (d *DurableSkipList[T]) sync() error {
+	if err := d.log.Sync(); err != nil {
+		d.err = err
+		return err
+	}
+	d.lastSync = time.Now()
+	return nil
+}
+
+// maybeCheckpoint runs an automatic checkpoint if one is due. The write that
+// triggered it is already logged, so a failure is kept for Sync to report
+// rather than returned as the write's error.
+func (d *DurableSkipList[T]) maybeCheckpoint() {
+	if d.opts.CheckpointEvery > 0 && d.records >= d.opts.CheckpointEvery {
+		if err := d.checkpoint(); err != nil {
+			d.checkpointErr = err
+		}
+	}
+}
+
+// Checkpoint writes a snapshot of the list and empties the log.
+//
+// The snapshot is written to a temporary file and renamed into place, so a
+// crash leaves either the old or the new checkpoint. A crash after the rename
+// but before the log is emptied is harmless: replaying Add and Delete records
+// over a checkpoint that already contains them is a no-op.
+func (d *DurableSkipList[T]) Checkpoint() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checkpointErr = nil
+	return d.checkpoint()
+}
+
+func (d *DurableSkipList[T]) checkpoint() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	tmp := d.snapshotPath() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	err = d.list.Snapshot(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, d.snapshotPath())
+	}
+	if err == nil {
+		err = syncDir(filepath.Dir(d.path))
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := d.log.Truncate(0); err != nil {
+		d.err = err
+		return err
+	}
+	if _, err := d.log.Seek(0, io.SeekStart); err != nil {
+		d.err = err
+		return err
+	}
+	d.records = 0
+	return d.sync()
+}
+
+// syncDir fsyncs a directory so a rename inside it is durable.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+func (d *DurableSkipList[T]) snapshotPath() string {
+	return d.path + ".snap"
+}
+
+// Sync fsyncs the log, making every write so far durable regardless of the
+// sync policy. If an automatic checkpoint has failed since the last Sync or
+// Checkpoint, Sync returns that error once the log is synced; the writes are
+// durable in the log either way.
+func (d *DurableSkipList[T]) Sync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	if err := d.sync(); err != nil {
+		return err
+	}
+	err := d.checkpointErr
+	d.checkpointErr = nil
+	return err
+}
+
+// Close syncs and closes the log. The list must not be used afterwards.
+func (d *DurableSkipList[T]) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var err error
+	if d.err == nil {
+		err = d.sync()
+	}
+	if closeErr := d.log.Close(); err == nil {
+		err = closeErr
+	}
+	d.err = errors.New("skiplist: durable list is closed")
+	return err
+}
+
+// Contains checks if val exists in the list.
+func (d *DurableSkipList[T]) Contains(val T) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.list.Contains(val)
+}
+
+// Len returns the number of elements in the list.
+func (d *DurableSkipList[T]) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.list.Len()
+}
+
+// Range iterates over all elements in ascending order under the read lock. If
+// fn returns false, iteration stops. fn must not call any method on d, as for
+// SyncSkipList.View.
+func (d *DurableSkipList[T]) Range(fn func(val T) bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	d.list.Range(fn)
+}
+
+// View calls fn with read-only access to the list under the read lock, like
+// SyncSkipList.View. fn must not call any method on d.
+func (d *DurableSkipList[T]) View(fn func(ro ReadOnly[T])) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	fn(readOnly[T]{d.list})
+}
@@ -0,0 +1,307 @@
+package skiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/anchor54/SkipList/internal/record"
+)
+
+// ------------------------------------------------------------
+// Durable helpers
+// ------------------------------------------------------------
+
+func openDurableInts(t *testing.T, path string, opts DurableOptions[int]) *DurableSkipList[int] {
+	t.Helper()
+	d, err := OpenDurable[int](path, opts)
+	if err != nil {
+		t.Fatalf("OpenDurable returned %v", err)
+	}
+	return d
+}
+
+func durableValues(d *DurableSkipList[int]) []int {
+	vals := []int{}
+	d.Range(func(val int) bool {
+		vals = append(vals, val)
+		return true
+	})
+	return vals
+}
+
+func modelValues(model map[int]bool) []int {
+	vals := []int{}
+	for v := range model {
+		vals = append(vals, v)
+	}
+	sort.Ints(vals)
+	return vals
+}
+
+// ------------------------------------------------------------
+// Durable tests
+// ------------------------------------------------------------
+
+func TestDurable_ReopenReplaysLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{})
+	for _, v := range []int{5, 1, 9, 3} {
+		if err := d.Add(v); err != nil {
+			t.Fatalf("Add returned %v", err)
+		}
+	}
+	if err := d.Delete(9); err != nil {
+		t.Fatalf("Delete returned %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	if err := d.Add(7); err == nil {
+		t.Fatalf("Add after Close should fail")
+	}
+
+	d = openDurableInts(t, path, DurableOptions[int]{})
+	defer d.Close()
+	if got := durableValues(d); !slicesEqual(got, []int{1, 3, 5}) {
+		t.Fatalf("expected [1 3 5] after reopening, got %v", got)
+	}
+}
+
+func TestDurable_CrashAtEveryOffset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{Sync: SyncNever})
+
+	// remember the model after every record, keyed by the log size that holds it
+	rng := rand.New(rand.NewSource(43))
+	model := map[int]bool{}
+	states := map[int64][]int{0: {}}
+	for i := 0; i < 60; i++ {
+		v := rng.Intn(20)
+		if model[v] {
+			d.Delete(v)
+			delete(model, v)
+		} else {
+			d.Add(v)
+			model[v] = true
+		}
+		info, _ := os.Stat(path)
+		states[info.Size()] = modelValues(model)
+	}
+	d.Close()
+
+	log, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile returned %v", err)
+	}
+
+	var want []int
+	var boundary int64
+	for cut := int64(0); cut <= int64(len(log)); cut++ {
+		if state, ok := states[cut]; ok {
+			want, boundary = state, cut
+		}
+
+		crashed := filepath.Join(dir, "crashed.log")
+		if err := os.WriteFile(crashed, log[:cut], 0o644); err != nil {
+			t.Fatalf("WriteFile returned %v", err)
+		}
+		r := openDurableInts(t, crashed, DurableOptions[int]{Sync: SyncNever})
+		if got := durableValues(r); !slicesEqual(got, want) {
+			t.Fatalf("cut at %d: got %v, want %v", cut, got, want)
+		}
+		if info, _ := os.Stat(crashed); info.Size() != boundary {
+			t.Fatalf("cut at %d: torn tail should be truncated to %d, log is %d bytes", cut, boundary, info.Size())
+		}
+
+		// the log must accept appends after the truncation
+		r.Add(100)
+		r.Close()
+		r = openDurableInts(t, crashed, DurableOptions[int]{Sync: SyncNever})
+		if !r.Contains(100) || r.Len() != len(want)+1 {
+			t.Fatalf("cut at %d: write after recovery was lost", cut)
+		}
+		r.Close()
+	}
+}
+
+func TestDurable_CorruptTailRecordIsDropped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{})
+	d.Add(1)
+	d.Add(2)
+	d.Close()
+
+	log, _ := os.ReadFile(path)
+	log[len(log)-1] ^= 0xff
+	os.WriteFile(path, log, 0o644)
+
+	d = openDurableInts(t, path, DurableOptions[int]{})
+	defer d.Close()
+	if got := durableValues(d); !slicesEqual(got, []int{1}) {
+		t.Fatalf("expected [1], got %v", got)
+	}
+}
+
+func TestDurable_CorruptMiddleRecordFailsOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{})
+	for i := 1; i <= 3; i++ {
+		d.Add(i)
+	}
+	d.Close()
+
+	// flip a bit in the payload of the second record; the third stays intact
+	log, _ := os.ReadFile(path)
+	recordSize := len(log) / 3
+	log[recordSize+record.HeaderSize] ^= 0x01
+	os.WriteFile(path, log, 0o644)
+
+	if _, err := OpenDurable[int](path, DurableOptions[int]{}); !errors.Is(err, ErrCorruptLog) {
+		t.Fatalf("expected ErrCorruptLog, got %v", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, log) {
+		t.Fatal("a corrupt log must not be truncated")
+	}
+}
+
+func TestDurable_CorruptMiddleLengthFailsOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{})
+	for i := 1; i <= 100; i++ {
+		d.Add(i)
+	}
+	d.Close()
+
+	// a first-record length past the end of the file must not pass for a torn tail
+	log, _ := os.ReadFile(path)
+	binary.LittleEndian.PutUint32(log, 1<<20)
+	os.WriteFile(path, log, 0o644)
+
+	if _, err := OpenDurable[int](path, DurableOptions[int]{}); !errors.Is(err, ErrCorruptLog) {
+		t.Fatalf("expected ErrCorruptLog, got %v", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, log) {
+		t.Fatal("a corrupt log must not be truncated")
+	}
+}
+
+func TestDurable_CheckpointTruncatesLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{Sync: SyncInterval, CheckpointEvery: 50})
+	for i := 0; i < 120; i++ {
+		d.Add(i)
+	}
+	d.Delete(7)
+	d.Close()
+
+	// 121 records with a checkpoint every 50 leave 21 in the log
+	info, _ := os.Stat(path)
+	if info.Size() == 0 || info.Size() > 21*(record.HeaderSize+3) {
+		t.Fatalf("log should only hold the records since the last checkpoint, it is %d bytes", info.Size())
+	}
+
+	d = openDurableInts(t, path, DurableOptions[int]{})
+	defer d.Close()
+	if d.Len() != 119 || d.Contains(7) || !d.Contains(119) {
+		t.Fatalf("checkpoint and log replay lost writes: Len %d", d.Len())
+	}
+}
+
+func TestDurable_FailedAutoCheckpointDoesNotFailWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{CheckpointEvery: 2})
+
+	// a directory in the way of the temporary snapshot makes checkpoints fail
+	blocker := path + ".snap.tmp"
+	if err := os.Mkdir(blocker, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := d.Add(i); err != nil {
+			t.Fatalf("Add(%d) was logged and applied, but returned %v", i, err)
+		}
+	}
+	if err := d.Sync(); err == nil {
+		t.Fatal("Sync should report the failed checkpoint")
+	}
+	if err := d.Sync(); err != nil {
+		t.Fatalf("the checkpoint failure should be reported once, got %v", err)
+	}
+
+	// once the checkpoint can be written, the next write retries it
+	os.Remove(blocker)
+	if err := d.Add(4); err != nil {
+		t.Fatalf("Add(4) returned %v", err)
+	}
+	if _, err := os.Stat(path + ".snap"); err != nil {
+		t.Fatalf("the retried checkpoint was not written: %v", err)
+	}
+	d.Close()
+
+	d = openDurableInts(t, path, DurableOptions[int]{})
+	defer d.Close()
+	if got := durableValues(d); !slicesEqual(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("expected [1 2 3 4], got %v", got)
+	}
+}
+
+func TestDurable_CrashBeforeLogTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.log")
+	d := openDurableInts(t, path, DurableOptions[int]{})
+	d.Add(1)
+	d.Add(2)
+	d.Delete(1)
+	d.Add(1)
+	d.Delete(2)
+	stale, _ := os.ReadFile(path)
+	if err := d.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint returned %v", err)
+	}
+	d.Close()
+
+	// as if the process died between renaming the snapshot and emptying the log
+	os.WriteFile(path, stale, 0o644)
+
+	d = openDurableInts(t, path, DurableOptions[int]{})
+	defer d.Close()
+	if got := durableValues(d); !slicesEqual(got, []int{1}) {
+		t.Fatalf("replaying the log over its checkpoint should give [1], got %v", got)
+	}
+}
+
+func TestDurable_CustomTypesNeedCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.log")
+	if _, err := OpenComparableDurable[account](path, DurableOptions[account]{}); !errors.Is(err, ErrNoCodec) {
+		t.Fatalf("expected ErrNoCodec, got %v", err)
+	}
+
+	d, err := OpenComparableDurable(path, DurableOptions[account]{Codec: accountCodec{}})
+	if err != nil {
+		t.Fatalf("OpenComparableDurable returned %v", err)
+	}
+	d.Add(account{id: "alice", balance: 5})
+	d.Checkpoint()
+	d.Add(account{id: "bob", balance: 7})
+	d.Close()
+
+	d, err = OpenComparableDurable(path, DurableOptions[account]{Codec: accountCodec{}})
+	if err != nil {
+		t.Fatalf("OpenComparableDurable returned %v", err)
+	}
+	defer d.Close()
+	d.View(func(ro ReadOnly[account]) {
+		if node, found := ro.SearchByValue(account{id: "bob"}); !found || node.Value().balance != 7 {
+			t.Fatalf("bob should be restored from the log")
+		}
+		if node, found := ro.SearchByValue(account{id: "alice"}); !found || node.Value().balance != 5 {
+			t.Fatalf("alice should be restored from the checkpoint")
+		}
+	})
+}
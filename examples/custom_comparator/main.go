@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	skiplist "github.com/anchor54/SkipList"
@@ -30,6 +32,22 @@ func (p Person) Compare(other Person) int {
 	return 0
 }
 
+// PersonCodec encodes a Person for binary serialization: the age as a varint
+// followed by the name.
+type PersonCodec struct{}
+
+func (PersonCodec) Encode(p Person) ([]byte, error) {
+	return append(binary.AppendVarint(nil, int64(p.Age)), p.Name...), nil
+}
+
+func (PersonCodec) Decode(data []byte) (Person, error) {
+	age, n := binary.Varint(data)
+	if n <= 0 {
+		return Person{}, errors.New("invalid person")
+	}
+	return Person{Name: string(data[n:]), Age: int(age)}, nil
+}
+
 func main() {
 	// Create a skip list for Person objects
 	sl := skiplist.NewComparableSkipList[Person]()
@@ -77,4 +95,20 @@ func main() {
 	// Remove a person
 	sl.Delete(Person{"Charlie", 35})
 	fmt.Printf("\nAfter removing Charlie, skip list length: %d\n", sl.Len())
+
+	// Serialize the list and restore it into a new one
+	sl.UseCodec(PersonCodec{})
+	data, err := sl.MarshalBinary()
+	if err != nil {
+		fmt.Println("marshal failed:", err)
+		return
+	}
+
+	restored := skiplist.NewComparableSkipList[Person]()
+	restored.UseCodec(PersonCodec{})
+	if err := restored.UnmarshalBinary(data); err != nil {
+		fmt.Println("unmarshal failed:", err)
+		return
+	}
+	fmt.Printf("Restored %d people from %d bytes\n", restored.Len(), len(data))
 }
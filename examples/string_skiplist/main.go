@@ -40,6 +40,14 @@ func main() {
 		}
 	}
 
+	// List languages sharing a prefix
+	fmt.Println("\nLanguages starting with \"J\":")
+	skiplist.PrefixRange(sl, "J", func(lang string) bool {
+		fmt.Printf("  - %s\n", lang)
+		return true
+	})
+	fmt.Printf("Languages starting with \"R\": %d\n", skiplist.CountPrefix(sl, "R"))
+
 	// Get languages by position
 	fmt.Println("\nLanguages by rank:")
 	for rank := 1; rank <= 3; rank++ {
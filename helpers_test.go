@@ -1,6 +1,9 @@
 package skiplist
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // ------------------------------------------------------------
 // Helper functions
@@ -10,7 +13,7 @@ func assertOrder(t *testing.T, sl *SkipList[int], expected []int) {
 	t.Helper()
 
 	i := 0
-	for curr := sl.head.forward[0]; curr != nil && curr.forward[0] != nil; curr = curr.forward[0] {
+	for curr := sl.head.levels[0].next; curr != nil && curr.levels[0].next != nil; curr = curr.levels[0].next {
 		if i >= len(expected) {
 			t.Fatalf("skiplist has more elements than expected; extra element %d", curr.val)
 		}
@@ -30,7 +33,7 @@ func assertOrder(t *testing.T, sl *SkipList[int], expected []int) {
 func collectLevelValues(sl *SkipList[int]) [][]int {
 	levels := make([][]int, sl.maxLevel+1)
 	for level := 0; level <= sl.maxLevel; level++ {
-		for curr := sl.head.forward[level]; curr != nil && curr != sl.tail; curr = curr.forward[level] {
+		for curr := sl.head.levels[level].next; curr != nil && curr != sl.tail; curr = curr.levels[level].next {
 			levels[level] = append(levels[level], curr.val)
 		}
 	}
@@ -75,22 +78,31 @@ func removeOne(vals []int, target int) ([]int, bool) {
 
 // Find a node that appears at more than one level (height > 0).
 func findMultiLevelNode(sl *SkipList[int]) *Node[int] {
-	for curr := sl.head.forward[0]; curr != nil && curr != sl.tail; curr = curr.forward[0] {
-		if len(curr.forward) > 1 {
+	for curr := sl.head.levels[0].next; curr != nil && curr != sl.tail; curr = curr.levels[0].next {
+		if len(curr.levels) > 1 {
 			return curr
 		}
 	}
 	return nil
 }
 
+// spansOf returns a copy of the span at every level of n's tower.
+func spansOf(n *Node[int]) []int {
+	spans := make([]int, len(n.levels))
+	for i, lvl := range n.levels {
+		spans[i] = lvl.span
+	}
+	return spans
+}
+
 // findNode returns the node with value v (or nil).
 func findNode(s *SkipList[int], v int) *Node[int] {
 	for n := s.head; n != nil; {
 		// traverse level 0 list
-		if len(n.forward) == 0 {
+		if len(n.levels) == 0 {
 			return nil
 		}
-		n = n.forward[0]
+		n = n.levels[0].next
 		if n == nil {
 			return nil
 		}
@@ -112,13 +124,13 @@ func checkSpans(t *testing.T, s *SkipList[int], v int, expectedSpans []int) {
 	if n == nil {
 		t.Fatalf("value %d not found in skiplist", v)
 	}
-	if len(n.skips) != len(expectedSpans) {
-		t.Fatalf("value %d: span length mismatch: got %d expected %d", v, len(n.skips), len(expectedSpans))
+	if len(n.levels) != len(expectedSpans) {
+		t.Fatalf("value %d: span length mismatch: got %d expected %d", v, len(n.levels), len(expectedSpans))
 	}
 	for i, want := range expectedSpans {
-		got := n.skips[i]
+		got := n.levels[i].span
 		if got != want {
-			t.Fatalf("value %d: span level %d: got %d, want %d", v, i, got, want)
+			t.Fatalf("value %d: span level %d: got %d, want %d\n%s", v, i, got, want, dumpString(s))
 		}
 	}
 }
@@ -132,3 +144,10 @@ func contains(slice []int, val int) bool {
 	}
 	return false
 }
+
+// dumpString returns the Dump diagram of s, for failure messages.
+func dumpString(s *SkipList[int]) string {
+	var b strings.Builder
+	s.Dump(&b)
+	return b.String()
+}
@@ -23,7 +23,7 @@ func TestInsertAtLevel_Level0(t *testing.T) {
 
 	// Verify nodes only exist at level 0
 	node5 := findNode(s, 5)
-	if node5 == nil || len(node5.forward) != 1 {
+	if node5 == nil || len(node5.levels) != 1 {
 		t.Fatalf("node 5 should have exactly 1 forward pointer (level 0)")
 	}
 }
@@ -70,8 +70,8 @@ func TestInsertAtLevel_MaxLevelCap(t *testing.T) {
 	if node == nil {
 		t.Fatalf("node 42 not found")
 	}
-	if len(node.forward) != MaxLevelCap+1 {
-		t.Fatalf("node at MAX_LEVEL_CAP should have %d forward pointers, got %d", MaxLevelCap+1, len(node.forward))
+	if len(node.levels) != MaxLevelCap+1 {
+		t.Fatalf("node at MAX_LEVEL_CAP should have %d forward pointers, got %d", MaxLevelCap+1, len(node.levels))
 	}
 }
 
@@ -152,8 +152,8 @@ func TestInsertAtLevel_ForwardPointersCorrectness(t *testing.T) {
 
 	// Verify forward pointers are non-nil and point to correct nodes
 	for level := 0; level <= s.maxLevel; level++ {
-		for curr := s.head; curr != s.tail && curr.forward[level] != nil; {
-			next := curr.forward[level]
+		for curr := s.head; curr != s.tail && curr.levels[level].next != nil; {
+			next := curr.levels[level].next
 			if next == nil {
 				t.Fatalf("nil forward pointer at level %d from node %d", level, curr.val)
 			}
@@ -183,13 +183,13 @@ func TestInsertAtLevel_InsertAtBeginning(t *testing.T) {
 
 	// Verify node 10 only appears at level 0
 	node10 := findNode(s, 10)
-	if node10 == nil || len(node10.forward) != 1 {
+	if node10 == nil || len(node10.levels) != 1 {
 		t.Fatalf("node 10 should have exactly 1 forward pointer")
 	}
 
 	// Verify node 50 appears at levels 0, 1, 2
 	node50 := findNode(s, 50)
-	if node50 == nil || len(node50.forward) != 3 {
+	if node50 == nil || len(node50.levels) != 3 {
 		t.Fatalf("node 50 should have 3 forward pointers")
 	}
 }
@@ -205,12 +205,12 @@ func TestInsertAtLevel_InsertAtEnd(t *testing.T) {
 
 	// Verify node 100 only appears at level 0
 	node100 := findNode(s, 100)
-	if node100 == nil || len(node100.forward) != 1 {
+	if node100 == nil || len(node100.levels) != 1 {
 		t.Fatalf("node 100 should have exactly 1 forward pointer")
 	}
 
 	// Verify forward pointer of 100 points to tail
-	if node100.forward[0] != s.tail {
+	if node100.levels[0].next != s.tail {
 		t.Fatalf("node 100 should point to tail at level 0")
 	}
 }
@@ -226,13 +226,13 @@ func TestInsertAtLevel_InsertInMiddle(t *testing.T) {
 
 	// Verify node 30 appears at levels 0 and 1
 	node30 := findNode(s, 30)
-	if node30 == nil || len(node30.forward) != 2 {
+	if node30 == nil || len(node30.levels) != 2 {
 		t.Fatalf("node 30 should have 2 forward pointers")
 	}
 
 	// Verify forward pointers
-	if node30.forward[0].val != 50 {
-		t.Fatalf("node 30 at level 0 should point to 50, got %d", node30.forward[0].val)
+	if node30.levels[0].next.val != 50 {
+		t.Fatalf("node 30 at level 0 should point to 50, got %d", node30.levels[0].next.val)
 	}
 }
 
@@ -252,26 +252,26 @@ func TestInsertAtLevel_SkipCountsComprehensive(t *testing.T) {
 	node30 := findNode(s, 30)
 	node40 := findNode(s, 40)
 
-	if node10 == nil || len(node10.skips) != 2 {
+	if node10 == nil || len(node10.levels) != 2 {
 		t.Fatalf("node 10 should have 2 skip counts")
 	}
-	if node20 == nil || len(node20.skips) != 3 {
+	if node20 == nil || len(node20.levels) != 3 {
 		t.Fatalf("node 20 should have 3 skip counts")
 	}
-	if node30 == nil || len(node30.skips) != 2 {
+	if node30 == nil || len(node30.levels) != 2 {
 		t.Fatalf("node 30 should have 2 skip counts")
 	}
-	if node40 == nil || len(node40.skips) != 1 {
+	if node40 == nil || len(node40.levels) != 1 {
 		t.Fatalf("node 40 should have 1 skip count")
 	}
 
 	// Verify skip counts are positive
-	for i, span := range node10.skips {
+	for i, span := range spansOf(node10) {
 		if span <= 0 {
 			t.Fatalf("node 10 skip count at level %d should be positive, got %d", i, span)
 		}
 	}
-	for i, span := range node20.skips {
+	for i, span := range spansOf(node20) {
 		if span <= 0 {
 			t.Fatalf("node 20 skip count at level %d should be positive, got %d", i, span)
 		}
@@ -299,8 +299,8 @@ func TestInsertAtLevel_IncreasingLevels(t *testing.T) {
 			t.Fatalf("node %d not found", val)
 		}
 		expectedLevel := i + 1 // +1 because level i means i+1 forward pointers
-		if len(node.forward) != expectedLevel {
-			t.Fatalf("node %d should have %d forward pointers, got %d", val, expectedLevel, len(node.forward))
+		if len(node.levels) != expectedLevel {
+			t.Fatalf("node %d should have %d forward pointers, got %d", val, expectedLevel, len(node.levels))
 		}
 	}
 }
@@ -334,32 +334,32 @@ func TestInsertAtLevel_HeadSkipCounts(t *testing.T) {
 	s.InsertAtLevel(10, 0)
 
 	// Head should have skip count of 1 at level 0 (pointing to 10)
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
 
 	s.InsertAtLevel(20, 2)
 
 	// Head should have skip count of 1 at level 0 (pointing to 10)
-	if s.head.skips[0] != 1 {
-		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.skips[0])
+	if s.head.levels[0].span != 1 {
+		t.Fatalf("head skip count at level 0 should be 1, got %d", s.head.levels[0].span)
 	}
 
 	// Head should have skip count of 2 at level 1 (pointing to 20, skipping 10)
-	if s.head.skips[1] != 2 {
-		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.skips[1])
+	if s.head.levels[1].span != 2 {
+		t.Fatalf("head skip count at level 1 should be 2, got %d", s.head.levels[1].span)
 	}
 
 	// Head should have skip count of 2 at level 2 (pointing to 20, skipping 10)
-	if s.head.skips[2] != 2 {
-		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.skips[2])
+	if s.head.levels[2].span != 2 {
+		t.Fatalf("head skip count at level 2 should be 2, got %d", s.head.levels[2].span)
 	}
 
 	// Head should have skip count of 3 at levels above maxLevel
 	// (initial 1 + 1 for node 10 + 1 for node 20 = 3)
 	for level := 3; level <= MaxLevelCap; level++ {
-		if s.head.skips[level] != 3 {
-			t.Fatalf("head skip count at level %d should be 3, got %d", level, s.head.skips[level])
+		if s.head.levels[level].span != 3 {
+			t.Fatalf("head skip count at level %d should be 3, got %d", level, s.head.levels[level].span)
 		}
 	}
 }
@@ -431,8 +431,8 @@ func TestInsertAtLevel_ComplexScenario(t *testing.T) {
 			t.Fatalf("node %d not found after insertion", ins.val)
 		}
 		expectedLevel := ins.level + 1
-		if len(node.forward) != expectedLevel {
-			t.Fatalf("node %d should have %d forward pointers, got %d", ins.val, expectedLevel, len(node.forward))
+		if len(node.levels) != expectedLevel {
+			t.Fatalf("node %d should have %d forward pointers, got %d", ins.val, expectedLevel, len(node.levels))
 		}
 	}
 }
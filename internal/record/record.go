@@ -0,0 +1,106 @@
+// Package record implements the framing of the write-ahead logs kept by
+// skiplist.DurableSkipList and the lsm package.
+package record
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+// ErrCorrupt is returned by Reader.Next for a record that fails a checksum or
+// has an invalid length while more of the log follows it. A crash
+// can only tear the last record, so such a record means the log itself is
+// damaged, and the records after it must not be dropped.
+var ErrCorrupt = errors.New("skiplist: corrupt log record")
+
+// A record is a header followed by the payload. The header is a 4-byte payload
+// length, a 4-byte CRC-32C of the payload and a 4-byte CRC-32C of the first
+// eight header bytes, all little-endian. The header checksum lets a reader
+// trust the length before reading the payload it describes. A log is a
+// sequence of records.
+const (
+	HeaderSize = 12
+	MaxSize    = 1 << 30
+)
+
+// readChunk bounds how much of a payload is allocated ahead of the bytes
+// actually read, so a length that is larger than the log costs no more memory
+// than the log itself.
+const readChunk = 64 << 10
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Append appends a record holding payload to dst.
+func Append(dst, payload []byte) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(payload)))
+	dst = binary.LittleEndian.AppendUint32(dst, crc32.Checksum(payload, crcTable))
+	dst = binary.LittleEndian.AppendUint32(dst, crc32.Checksum(dst[len(dst)-8:], crcTable))
+	return append(dst, payload...)
+}
+
+// Reader reads the records of a log in order.
+type Reader struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+// NewReader returns a reader for the log in r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the payload of the next record. At the end of the log it
+// returns io.EOF. If the log ends in a record torn by a crash, which is a
+// short header, a verified header with a short payload or a bad last record,
+// Next returns io.ErrUnexpectedEOF; the log can then be truncated to Offset. A
+// bad record followed by more data returns an error wrapping ErrCorrupt.
+func (rr *Reader) Next() ([]byte, error) {
+	var header [HeaderSize]byte
+	if n, err := io.ReadFull(rr.r, header[:]); err != nil {
+		if n == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	if crc32.Checksum(header[:8], crcTable) != binary.LittleEndian.Uint32(header[8:]) {
+		return nil, rr.bad("header checksum mismatch")
+	}
+	size := int(binary.LittleEndian.Uint32(header[0:]))
+	if size == 0 || size > MaxSize {
+		return nil, rr.bad("invalid length %d", size)
+	}
+
+	payload := make([]byte, 0, min(size, readChunk))
+	for len(payload) < size {
+		n := min(size-len(payload), readChunk)
+		payload = slices.Grow(payload, n)
+		if _, err := io.ReadFull(rr.r, payload[len(payload):len(payload)+n]); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		payload = payload[:len(payload)+n]
+	}
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, rr.bad("checksum mismatch")
+	}
+	rr.offset += HeaderSize + int64(size)
+	return payload, nil
+}
+
+// bad reports a bad record as a torn tail if nothing follows it, and as
+// corruption otherwise.
+func (rr *Reader) bad(format string, args ...any) error {
+	if _, err := rr.r.Peek(1); err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("%w at offset %d: %s", ErrCorrupt, rr.offset, fmt.Sprintf(format, args...))
+}
+
+// Offset returns the end of the last record Next returned.
+func (rr *Reader) Offset() int64 {
+	return rr.offset
+}
@@ -0,0 +1,85 @@
+package record
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Record tests
+// ------------------------------------------------------------
+
+func readRecords(data []byte) ([]string, int64, error) {
+	rr := NewReader(bytes.NewReader(data))
+	var payloads []string
+	for {
+		payload, err := rr.Next()
+		if err != nil {
+			return payloads, rr.Offset(), err
+		}
+		payloads = append(payloads, string(payload))
+	}
+}
+
+func TestReader_TornTailAndCorruption(t *testing.T) {
+	var log []byte
+	for _, p := range []string{"one", "two", "three"} {
+		log = Append(log, []byte(p))
+	}
+	second := HeaderSize + 3
+
+	// a length beyond the end of the file whose header checksum still matches
+	// can only be a torn write; one that fails the header checksum is damage
+	longLength := bytes.Clone(log)
+	binary.LittleEndian.PutUint32(longLength[second:], 1<<20)
+	longLengthAtTail := bytes.Clone(longLength[:second+HeaderSize+2])
+	binary.LittleEndian.PutUint32(longLengthAtTail[second+8:], crc32.Checksum(longLengthAtTail[second:second+8], crcTable))
+
+	badLength := bytes.Clone(log)
+	badLength[second] = 0
+	badLength[second+1] = 0
+	badLengthAtTail := bytes.Clone(log[:second+HeaderSize])
+	badLengthAtTail[second] = 0
+	flippedMiddle := bytes.Clone(log)
+	flippedMiddle[second+HeaderSize] ^= 1
+	flippedLast := bytes.Clone(log)
+	flippedLast[len(log)-1] ^= 1
+
+	cases := []struct {
+		name     string
+		data     []byte
+		payloads int
+		err      error
+	}{
+		{"intact", log, 3, io.EOF},
+		{"empty", nil, 0, io.EOF},
+		{"short header", log[:second+3], 1, io.ErrUnexpectedEOF},
+		{"short payload", log[:len(log)-2], 2, io.ErrUnexpectedEOF},
+		{"bad last record", flippedLast, 2, io.ErrUnexpectedEOF},
+		{"bad length at tail", badLengthAtTail, 1, io.ErrUnexpectedEOF},
+		{"bad middle record", flippedMiddle, 1, ErrCorrupt},
+		{"bad middle length", badLength, 1, ErrCorrupt},
+		{"middle length larger than the file", longLength, 1, ErrCorrupt},
+		{"verified length past a torn tail", longLengthAtTail, 1, io.ErrUnexpectedEOF},
+	}
+	for _, tc := range cases {
+		payloads, offset, err := readRecords(tc.data)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.err, err)
+		}
+		if len(payloads) != tc.payloads {
+			t.Fatalf("%s: expected %d records, got %v", tc.name, tc.payloads, payloads)
+		}
+		var want int64
+		for _, p := range payloads {
+			want += int64(HeaderSize + len(p))
+		}
+		if offset != want {
+			t.Fatalf("%s: expected offset %d, got %d", tc.name, want, offset)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package skiplist
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Interval is a closed interval [Lo, Hi] carrying a value.
+type Interval[T any, V comparable] struct {
+	Lo    T
+	Hi    T
+	Value V
+}
+
+// interval is the stored form of an Interval. It remembers every edge and node
+// it has been marked on so the markers can be removed exactly, regardless of
+// how the surrounding towers changed since they were placed. seq orders
+// intervals with equal endpoints by insertion.
+type interval[T any, V comparable] struct {
+	Interval[T, V]
+	seq   uint64
+	edges []intervalEdge[T, V]
+	nodes []*intervalNode[T, V]
+}
+
+type intervalEdge[T any, V comparable] struct {
+	from  *intervalNode[T, V]
+	level int
+}
+
+// The endpoints are kept in an ordinary SkipList, so their nodes share its
+// towers, allocation and linking. Only key takes part in comparisons, so an
+// endpoint holding just a key is a search probe; the markers live behind a
+// pointer and are not copied with it.
+type endpoint[T any, V comparable] struct {
+	key T
+	*endpointMarks[T, V]
+}
+
+type endpointMarks[T any, V comparable] struct {
+	// markers[i] holds the intervals that cover the open edge from this node
+	// to its successor at level i.
+	markers []map[*interval[T, V]]struct{}
+	// eqMarkers holds the intervals marked on this node's key itself.
+	eqMarkers map[*interval[T, V]]struct{}
+	// owners holds the intervals whose Lo endpoint is this node.
+	owners []*interval[T, V]
+	// refs counts the interval endpoints referencing this node.
+	refs int
+}
+
+type intervalNode[T any, V comparable] = Node[endpoint[T, V]]
+
+// IntervalSkipList stores closed intervals and answers stabbing and overlap
+// queries. It follows Hanson's interval skip list: interval endpoints are kept
+// in a skip list and each interval is marked on the highest edges that lie
+// entirely inside it, so a single descent towards a point collects every
+// interval containing that point.
+type IntervalSkipList[T any, V comparable] struct {
+	endpoints  *SkipList[endpoint[T, V]]
+	length     int
+	seq        uint64
+	comparator Comparator[T]
+}
+
+// NewIntervalSkipList creates an interval skip list for ordered endpoint types.
+func NewIntervalSkipList[T cmp.Ordered, V comparable]() *IntervalSkipList[T, V] {
+	return newIntervalSkipList[T, V](cmp.Compare[T])
+}
+
+// NewComparableIntervalSkipList creates an interval skip list for endpoint types
+// that implement the Comparable interface.
+func NewComparableIntervalSkipList[T Comparable[T], V comparable]() *IntervalSkipList[T, V] {
+	return newIntervalSkipList[T, V](func(a, b T) int {
+		return a.Compare(b)
+	})
+}
+
+func newIntervalSkipList[T any, V comparable](comparator Comparator[T]) *IntervalSkipList[T, V] {
+	endpoints := newSkipList(func(a, b endpoint[T, V]) int {
+		return comparator(a.key, b.key)
+	})
+	// no interval starts before the first endpoint, so the head's markers stay
+	// empty, but they are read while re-placing markers
+	endpoints.head.val.endpointMarks = &endpointMarks[T, V]{
+		markers: make([]map[*interval[T, V]]struct{}, MaxLevelCap+1),
+	}
+	return &IntervalSkipList[T, V]{
+		endpoints:  endpoints,
+		comparator: comparator,
+	}
+}
+
+// Len returns the number of intervals stored.
+func (il *IntervalSkipList[T, V]) Len() int {
+	return il.length
+}
+
+// Insert adds the closed interval [lo, hi] with value v. Intervals are kept as a
+// multiset, so inserting the same interval twice stores it twice. It returns
+// false without inserting anything if lo > hi.
+func (il *IntervalSkipList[T, V]) Insert(lo, hi T, v V) bool {
+	if il.comparator(lo, hi) > 0 {
+		return false
+	}
+
+	left := il.insertEndpoint(lo)
+	right := il.insertEndpoint(hi)
+
+	il.seq++
+	iv := &interval[T, V]{Interval: Interval[T, V]{Lo: lo, Hi: hi, Value: v}, seq: il.seq}
+	left.val.owners = append(left.val.owners, iv)
+	il.placeMarkers(iv, left, right)
+
+	il.length++
+	return true
+}
+
+// Delete removes one interval equal to [lo, hi] with value v. It returns false
+// if no such interval is stored.
+func (il *IntervalSkipList[T, V]) Delete(lo, hi T, v V) bool {
+	left := il.findNode(lo)
+	if left == nil {
+		return false
+	}
+
+	idx := -1
+	for i, iv := range left.val.owners {
+		if iv.Value == v && il.comparator(iv.Hi, hi) == 0 {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return false
+	}
+
+	iv := left.val.owners[idx]
+	left.val.owners = append(left.val.owners[:idx], left.val.owners[idx+1:]...)
+	il.removeMarkers(iv)
+
+	right := il.findNode(hi)
+	il.releaseEndpoint(left)
+	il.releaseEndpoint(right)
+
+	il.length--
+	return true
+}
+
+// Stab returns every interval that contains x, in no particular order.
+//
+// Time Complexity: O(log n + k) average, where k is the number of results.
+func (il *IntervalSkipList[T, V]) Stab(x T) []Interval[T, V] {
+	return intervals(il.stab(x, nil))
+}
+
+// StabSorted returns every interval that contains x, ordered by Lo, then Hi,
+// then insertion.
+//
+// Time Complexity: O(log n + k log k) average, where k is the number of results.
+func (il *IntervalSkipList[T, V]) StabSorted(x T) []Interval[T, V] {
+	return il.sorted(il.stab(x, nil))
+}
+
+func (il *IntervalSkipList[T, V]) stab(x T, out []*interval[T, V]) []*interval[T, V] {
+	curr := il.endpoints.head
+
+	for currLevel := il.endpoints.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil && il.comparator(curr.levels[currLevel].next.val.key, x) <= 0 {
+			curr = curr.levels[currLevel].next
+		}
+
+		// x sits exactly on an endpoint: the node's own markers are the answer
+		if curr != il.endpoints.head && il.comparator(curr.val.key, x) == 0 {
+			return appendMarkers(out, curr.val.eqMarkers)
+		}
+
+		// x lies strictly inside the edge from curr to its successor
+		if curr.levels[currLevel].next != nil {
+			out = appendMarkers(out, curr.val.markers[currLevel])
+		}
+	}
+	return out
+}
+
+// Overlapping returns every interval that shares at least one point with the
+// closed interval [lo, hi], in no particular order. It returns nil if lo > hi.
+//
+// Time Complexity: O(log n + k) average, where k is the number of results.
+func (il *IntervalSkipList[T, V]) Overlapping(lo, hi T) []Interval[T, V] {
+	return intervals(il.overlapping(lo, hi))
+}
+
+// OverlappingSorted returns the intervals Overlapping returns, ordered like
+// StabSorted.
+//
+// Time Complexity: O(log n + k log k) average, where k is the number of results.
+func (il *IntervalSkipList[T, V]) OverlappingSorted(lo, hi T) []Interval[T, V] {
+	return il.sorted(il.overlapping(lo, hi))
+}
+
+func (il *IntervalSkipList[T, V]) overlapping(lo, hi T) []*interval[T, V] {
+	if il.comparator(lo, hi) > 0 {
+		return nil
+	}
+
+	// intervals that start at or before lo overlap iff they contain lo
+	out := il.stab(lo, nil)
+
+	// the remaining ones start inside (lo, hi]
+	curr := il.endpoints.head
+	for currLevel := il.endpoints.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil && il.comparator(curr.levels[currLevel].next.val.key, lo) <= 0 {
+			curr = curr.levels[currLevel].next
+		}
+	}
+	for curr = curr.levels[0].next; curr != nil && il.comparator(curr.val.key, hi) <= 0; curr = curr.levels[0].next {
+		out = append(out, curr.val.owners...)
+	}
+	return out
+}
+
+// sorted orders query results by Lo, then Hi, then insertion. Markers are kept
+// in sets, so the order they are collected in carries no meaning.
+func (il *IntervalSkipList[T, V]) sorted(ivs []*interval[T, V]) []Interval[T, V] {
+	slices.SortFunc(ivs, func(a, b *interval[T, V]) int {
+		if c := il.comparator(a.Lo, b.Lo); c != 0 {
+			return c
+		}
+		if c := il.comparator(a.Hi, b.Hi); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.seq, b.seq)
+	})
+	return intervals(ivs)
+}
+
+// intervals copies query results out of their internal records.
+func intervals[T any, V comparable](ivs []*interval[T, V]) []Interval[T, V] {
+	if len(ivs) == 0 {
+		return nil
+	}
+	out := make([]Interval[T, V], len(ivs))
+	for i, iv := range ivs {
+		out[i] = iv.Interval
+	}
+	return out
+}
+
+func appendMarkers[T any, V comparable](out []*interval[T, V], set map[*interval[T, V]]struct{}) []*interval[T, V] {
+	for iv := range set {
+		out = append(out, iv)
+	}
+	return out
+}
+
+func (il *IntervalSkipList[T, V]) findNode(key T) *intervalNode[T, V] {
+	node, _ := il.endpoints.SearchByValue(endpoint[T, V]{key: key})
+	return node
+}
+
+// insertEndpoint returns the node for key, creating it if needed, and takes a
+// reference on it. Intervals marked on the edges split by a new node are
+// re-placed so that every edge keeps an exact set of covering intervals.
+func (il *IntervalSkipList[T, V]) insertEndpoint(key T) *intervalNode[T, V] {
+	var f finger[endpoint[T, V]]
+	il.endpoints.descend(&f, endpoint[T, V]{key: key}, il.endpoints.maxLevel)
+
+	if next := f.hierarchy[0].levels[0].next; next != nil && il.comparator(next.val.key, key) == 0 {
+		next.val.refs++
+		return next
+	}
+
+	lvl := randomLevel()
+	affected := map[*interval[T, V]]struct{}{}
+	for i := 0; i <= min(lvl, il.endpoints.maxLevel); i++ {
+		for iv := range f.hierarchy[i].val.markers[i] {
+			affected[iv] = struct{}{}
+		}
+	}
+	for iv := range affected {
+		il.removeMarkers(iv)
+	}
+
+	newNode := il.endpoints.link(&f, endpoint[T, V]{key: key, endpointMarks: &endpointMarks[T, V]{
+		markers: make([]map[*interval[T, V]]struct{}, lvl+1),
+		refs:    1,
+	}}, lvl)
+
+	il.replaceMarkers(affected)
+	return newNode
+}
+
+// releaseEndpoint drops a reference on node and unlinks it once no interval
+// uses it as an endpoint any more.
+func (il *IntervalSkipList[T, V]) releaseEndpoint(node *intervalNode[T, V]) {
+	node.val.refs--
+	if node.val.refs > 0 {
+		return
+	}
+
+	var f finger[endpoint[T, V]]
+	il.endpoints.descend(&f, node.val, il.endpoints.maxLevel)
+
+	affected := map[*interval[T, V]]struct{}{}
+	for iv := range node.val.eqMarkers {
+		affected[iv] = struct{}{}
+	}
+	for i := range node.levels {
+		for iv := range f.hierarchy[i].val.markers[i] {
+			affected[iv] = struct{}{}
+		}
+		for iv := range node.val.markers[i] {
+			affected[iv] = struct{}{}
+		}
+	}
+	for iv := range affected {
+		il.removeMarkers(iv)
+	}
+
+	il.endpoints.unlink(&f, node)
+	il.replaceMarkers(affected)
+}
+
+func (il *IntervalSkipList[T, V]) replaceMarkers(ivs map[*interval[T, V]]struct{}) {
+	for iv := range ivs {
+		il.placeMarkers(iv, il.findNode(iv.Lo), il.findNode(iv.Hi))
+	}
+}
+
+// placeMarkers walks from left to right always taking the highest edge that
+// stays inside the interval, marking each edge taken and each node visited.
+// The walk climbs while the towers allow it and descends as it nears right,
+// giving O(log n) markers per interval on average.
+func (il *IntervalSkipList[T, V]) placeMarkers(iv *interval[T, V], left, right *intervalNode[T, V]) {
+	curr := left
+	il.markNode(iv, curr)
+
+	for curr != right {
+		lvl := len(curr.levels) - 1
+		for curr.levels[lvl].next == nil || il.comparator(curr.levels[lvl].next.val.key, iv.Hi) > 0 {
+			lvl--
+		}
+
+		if curr.val.markers[lvl] == nil {
+			curr.val.markers[lvl] = map[*interval[T, V]]struct{}{}
+		}
+		curr.val.markers[lvl][iv] = struct{}{}
+		iv.edges = append(iv.edges, intervalEdge[T, V]{from: curr, level: lvl})
+
+		curr = curr.levels[lvl].next
+		il.markNode(iv, curr)
+	}
+}
+
+func (il *IntervalSkipList[T, V]) markNode(iv *interval[T, V], node *intervalNode[T, V]) {
+	if node.val.eqMarkers == nil {
+		node.val.eqMarkers = map[*interval[T, V]]struct{}{}
+	}
+	node.val.eqMarkers[iv] = struct{}{}
+	iv.nodes = append(iv.nodes, node)
+}
+
+func (il *IntervalSkipList[T, V]) removeMarkers(iv *interval[T, V]) {
+	for _, e := range iv.edges {
+		delete(e.from.val.markers[e.level], iv)
+	}
+	for _, n := range iv.nodes {
+		delete(n.val.eqMarkers, iv)
+	}
+	iv.edges = iv.edges[:0]
+	iv.nodes = iv.nodes[:0]
+}
@@ -0,0 +1,216 @@
+package skiplist
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Interval skip list helpers
+// ------------------------------------------------------------
+
+type testInterval = Interval[int, int]
+
+func sortIntervals(ivs []testInterval) {
+	sort.Slice(ivs, func(i, j int) bool {
+		if ivs[i].Lo != ivs[j].Lo {
+			return ivs[i].Lo < ivs[j].Lo
+		}
+		if ivs[i].Hi != ivs[j].Hi {
+			return ivs[i].Hi < ivs[j].Hi
+		}
+		return ivs[i].Value < ivs[j].Value
+	})
+}
+
+// assertIntervals expects got to hold want in any order.
+func assertIntervals(t *testing.T, what string, got, want []testInterval) {
+	t.Helper()
+	got = slices.Clone(got)
+	sortIntervals(got)
+	assertSortedIntervals(t, what, got, want)
+}
+
+// assertSortedIntervals expects got in the order sorted queries return it.
+// Values grow with insertion in these tests, so sorting want by value breaks
+// ties the same way.
+func assertSortedIntervals(t *testing.T, what string, got, want []testInterval) {
+	t.Helper()
+	sortIntervals(want)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d intervals %v, want %d %v", what, len(got), got, len(want), want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: interval %d: got %v, want %v", what, i, got[i], want[i])
+		}
+	}
+}
+
+func bruteStab(ivs []testInterval, x int) []testInterval {
+	var out []testInterval
+	for _, iv := range ivs {
+		if iv.Lo <= x && x <= iv.Hi {
+			out = append(out, iv)
+		}
+	}
+	return out
+}
+
+func bruteOverlapping(ivs []testInterval, lo, hi int) []testInterval {
+	var out []testInterval
+	for _, iv := range ivs {
+		if iv.Lo <= hi && lo <= iv.Hi {
+			out = append(out, iv)
+		}
+	}
+	return out
+}
+
+// ------------------------------------------------------------
+// Interval skip list tests
+// ------------------------------------------------------------
+
+func TestIntervalSkipList_Empty(t *testing.T) {
+	il := NewIntervalSkipList[int, int]()
+
+	if got := il.Stab(5); len(got) != 0 {
+		t.Fatalf("Stab on empty list should return nothing, got %v", got)
+	}
+	if got := il.Overlapping(0, 10); len(got) != 0 {
+		t.Fatalf("Overlapping on empty list should return nothing, got %v", got)
+	}
+	if il.Delete(1, 2, 0) {
+		t.Fatal("Delete on empty list should return false")
+	}
+}
+
+func TestIntervalSkipList_StabEndpoints(t *testing.T) {
+	il := NewIntervalSkipList[int, int]()
+	il.Insert(10, 20, 1)
+	il.Insert(15, 25, 2)
+	il.Insert(20, 20, 3)
+
+	tests := []struct {
+		x    int
+		want []testInterval
+	}{
+		{9, nil},
+		{10, []testInterval{{10, 20, 1}}},
+		{15, []testInterval{{10, 20, 1}, {15, 25, 2}}},
+		{20, []testInterval{{10, 20, 1}, {15, 25, 2}, {20, 20, 3}}},
+		{21, []testInterval{{15, 25, 2}}},
+		{25, []testInterval{{15, 25, 2}}},
+		{26, nil},
+	}
+
+	for _, tc := range tests {
+		assertIntervals(t, "Stab", il.Stab(tc.x), tc.want)
+		assertSortedIntervals(t, "StabSorted", il.StabSorted(tc.x), tc.want)
+	}
+}
+
+func TestIntervalSkipList_ResultOrder(t *testing.T) {
+	il := NewIntervalSkipList[int, string]()
+	il.Insert(5, 9, "c")
+	il.Insert(1, 9, "b")
+	il.Insert(1, 9, "a")
+	il.Insert(1, 6, "d")
+	il.Insert(7, 8, "e")
+
+	want := []Interval[int, string]{{1, 6, "d"}, {1, 9, "b"}, {1, 9, "a"}, {5, 9, "c"}}
+	for range 20 {
+		got := il.StabSorted(6)
+		if !slices.Equal(got, want) {
+			t.Fatalf("StabSorted(6) = %v, want %v", got, want)
+		}
+	}
+	want = append(want, Interval[int, string]{7, 8, "e"})
+	if got := il.OverlappingSorted(6, 7); !slices.Equal(got, want) {
+		t.Fatalf("OverlappingSorted(6, 7) = %v, want %v", got, want)
+	}
+}
+
+func TestIntervalSkipList_InsertRejectsInverted(t *testing.T) {
+	il := NewIntervalSkipList[int, int]()
+
+	if il.Insert(5, 1, 0) {
+		t.Fatal("Insert with lo > hi should return false")
+	}
+	if il.Len() != 0 {
+		t.Fatalf("Len should be 0, got %d", il.Len())
+	}
+}
+
+func TestIntervalSkipList_DuplicatesAndDelete(t *testing.T) {
+	il := NewIntervalSkipList[int, string]()
+	il.Insert(1, 5, "a")
+	il.Insert(1, 5, "a")
+	il.Insert(1, 5, "b")
+
+	if il.Len() != 3 {
+		t.Fatalf("Len should be 3, got %d", il.Len())
+	}
+	if !il.Delete(1, 5, "a") {
+		t.Fatal("Delete of stored interval should return true")
+	}
+	if got := il.Stab(3); len(got) != 2 {
+		t.Fatalf("expected 2 intervals after deleting one duplicate, got %v", got)
+	}
+	if il.Delete(1, 6, "a") {
+		t.Fatal("Delete with a different hi should return false")
+	}
+	il.Delete(1, 5, "a")
+	il.Delete(1, 5, "b")
+	if got := il.Stab(3); len(got) != 0 {
+		t.Fatalf("expected no intervals, got %v", got)
+	}
+	if il.endpoints.Len() != 0 {
+		t.Fatal("all endpoint nodes should be unlinked once unused")
+	}
+}
+
+func TestIntervalSkipList_RandomAgainstBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(26))
+	il := NewIntervalSkipList[int, int]()
+	var live []testInterval
+
+	for step := 0; step < 3000; step++ {
+		if len(live) > 0 && rng.Intn(3) == 0 {
+			i := rng.Intn(len(live))
+			iv := live[i]
+			if !il.Delete(iv.Lo, iv.Hi, iv.Value) {
+				t.Fatalf("step %d: Delete(%v) returned false", step, iv)
+			}
+			live = append(live[:i], live[i+1:]...)
+		} else {
+			lo := rng.Intn(200)
+			iv := testInterval{Lo: lo, Hi: lo + rng.Intn(40), Value: step}
+			il.Insert(iv.Lo, iv.Hi, iv.Value)
+			live = append(live, iv)
+		}
+
+		if step%50 != 0 {
+			continue
+		}
+		if err := il.endpoints.Validate(); err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+		for x := -1; x <= 241; x += 3 {
+			assertIntervals(t, "Stab", il.Stab(x), bruteStab(live, x))
+			assertSortedIntervals(t, "StabSorted", il.StabSorted(x), bruteStab(live, x))
+		}
+		for q := 0; q < 20; q++ {
+			lo := rng.Intn(240)
+			hi := lo + rng.Intn(30)
+			assertIntervals(t, "Overlapping", il.Overlapping(lo, hi), bruteOverlapping(live, lo, hi))
+			assertSortedIntervals(t, "OverlappingSorted", il.OverlappingSorted(lo, hi), bruteOverlapping(live, lo, hi))
+		}
+	}
+
+	if il.Len() != len(live) {
+		t.Fatalf("Len mismatch: got %d, want %d", il.Len(), len(live))
+	}
+}
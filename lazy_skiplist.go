@@ -0,0 +1,252 @@
+package skiplist
+
+import (
+	"cmp"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// LazySkipList is a concurrent skip list set with fine-grained locking, after
+// the lazy skip list of Herlihy, Lev, Luchangco and Shavit.
+//
+// Add and Delete search without locks, then lock only the predecessors they
+// recorded and validate that nothing changed before linking or unlinking, so
+// writes to different parts of the list proceed in parallel. A node is
+// logically in the set once it is fully linked and until it is marked, which
+// lets Contains run without locks or retries. Like ConcurrentSkipList it has no
+// rank APIs, and Len is exact only when no writes are in flight.
+type LazySkipList[T any] struct {
+	head       *lazyNode[T]
+	length     atomic.Int64
+	comparator Comparator[T]
+}
+
+type lazyNode[T any] struct {
+	val         T
+	next        []atomic.Pointer[lazyNode[T]]
+	mu          sync.Mutex
+	marked      atomic.Bool
+	fullyLinked atomic.Bool
+}
+
+// NewLazySkipList creates a lazy skip list for ordered types.
+func NewLazySkipList[T cmp.Ordered]() *LazySkipList[T] {
+	return newLazySkipList(cmp.Compare[T])
+}
+
+// NewComparableLazySkipList creates a lazy skip list for types that implement
+// the Comparable interface.
+func NewComparableLazySkipList[T Comparable[T]]() *LazySkipList[T] {
+	return newLazySkipList(func(a, b T) int {
+		return a.Compare(b)
+	})
+}
+
+func newLazySkipList[T any](comparator Comparator[T]) *LazySkipList[T] {
+	head := &lazyNode[T]{next: make([]atomic.Pointer[lazyNode[T]], MaxLevelCap+1)}
+	head.fullyLinked.Store(true)
+
+	return &LazySkipList[T]{
+		head:       head,
+		comparator: comparator,
+	}
+}
+
+// find records the predecessor and successor of val at every level in
+// hierarchy and succs, without taking any locks. It returns the highest level
+// at which a node equal to val was found, or -1.
+func (sl *LazySkipList[T]) find(val T, hierarchy, succs *[MaxLevelCap + 1]*lazyNode[T]) int {
+	found := -1
+	pred := sl.head
+
+	for level := MaxLevelCap; level >= 0; level-- {
+		curr := pred.next[level].Load()
+		for curr != nil && sl.comparator(curr.val, val) < 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+
+		if found == -1 && curr != nil && sl.comparator(curr.val, val) == 0 {
+			found = level
+		}
+		hierarchy[level] = pred
+		succs[level] = curr
+	}
+	return found
+}
+
+// lockHierarchy locks the distinct predecessors in hierarchy from level 0 up
+// to top and reports whether valid holds for every level. It returns the
+// highest level whose predecessor it locked, for unlockHierarchy.
+//
+// Locks are always taken from the bottom level up, that is from larger to
+// smaller nodes, which keeps concurrent writers from deadlocking.
+func lockHierarchy[T any](hierarchy *[MaxLevelCap + 1]*lazyNode[T], top int, valid func(level int) bool) (int, bool) {
+	locked := -1
+	var prev *lazyNode[T]
+
+	for level := 0; level <= top; level++ {
+		if pred := hierarchy[level]; pred != prev {
+			pred.mu.Lock()
+			locked, prev = level, pred
+		}
+		if !valid(level) {
+			return locked, false
+		}
+	}
+	return locked, true
+}
+
+// unlockHierarchy releases the locks taken by lockHierarchy.
+func unlockHierarchy[T any](hierarchy *[MaxLevelCap + 1]*lazyNode[T], locked int) {
+	var prev *lazyNode[T]
+	for level := 0; level <= locked; level++ {
+		if pred := hierarchy[level]; pred != prev {
+			pred.mu.Unlock()
+			prev = pred
+		}
+	}
+}
+
+// Add inserts val and reports whether it was added. Adding an existing value
+// is a no-op and returns false.
+func (sl *LazySkipList[T]) Add(val T) bool {
+	var hierarchy, succs [MaxLevelCap + 1]*lazyNode[T]
+	top := randomLevel()
+
+	for {
+		if found := sl.find(val, &hierarchy, &succs); found != -1 {
+			existing := succs[found]
+			if existing.marked.Load() {
+				// being deleted; retry once it is unlinked
+				continue
+			}
+			for !existing.fullyLinked.Load() {
+				runtime.Gosched()
+			}
+			return false
+		}
+
+		locked, valid := lockHierarchy(&hierarchy, top, func(level int) bool {
+			pred, succ := hierarchy[level], succs[level]
+			return !pred.marked.Load() &&
+				(succ == nil || !succ.marked.Load()) &&
+				pred.next[level].Load() == succ
+		})
+		if !valid {
+			unlockHierarchy(&hierarchy, locked)
+			continue
+		}
+
+		node := &lazyNode[T]{val: val, next: make([]atomic.Pointer[lazyNode[T]], top+1)}
+		for level := 0; level <= top; level++ {
+			node.next[level].Store(succs[level])
+		}
+		for level := 0; level <= top; level++ {
+			hierarchy[level].next[level].Store(node)
+		}
+		node.fullyLinked.Store(true)
+		sl.length.Add(1)
+
+		unlockHierarchy(&hierarchy, locked)
+		return true
+	}
+}
+
+// Delete removes val and reports whether this call removed it.
+func (sl *LazySkipList[T]) Delete(val T) bool {
+	var hierarchy, succs [MaxLevelCap + 1]*lazyNode[T]
+	var victim *lazyNode[T]
+
+	for {
+		found := sl.find(val, &hierarchy, &succs)
+
+		if victim == nil {
+			// only a fully linked node found at its top level is safe to
+			// delete; otherwise it is still being added or already deleted
+			if found == -1 {
+				return false
+			}
+			candidate := succs[found]
+			if !candidate.fullyLinked.Load() || candidate.marked.Load() || len(candidate.next)-1 != found {
+				return false
+			}
+
+			candidate.mu.Lock()
+			if candidate.marked.Load() {
+				candidate.mu.Unlock()
+				return false
+			}
+			// marking is the linearization point
+			candidate.marked.Store(true)
+			victim = candidate
+		}
+
+		top := len(victim.next) - 1
+		locked, valid := lockHierarchy(&hierarchy, top, func(level int) bool {
+			pred := hierarchy[level]
+			return !pred.marked.Load() && pred.next[level].Load() == victim
+		})
+		if !valid {
+			unlockHierarchy(&hierarchy, locked)
+			continue
+		}
+
+		for level := top; level >= 0; level-- {
+			hierarchy[level].next[level].Store(victim.next[level].Load())
+		}
+		sl.length.Add(-1)
+
+		victim.mu.Unlock()
+		unlockHierarchy(&hierarchy, locked)
+		return true
+	}
+}
+
+// Contains checks if val exists in the list. It takes no locks and never
+// retries, so it is wait-free.
+func (sl *LazySkipList[T]) Contains(val T) bool {
+	_, found := sl.SearchByValue(val)
+	return found
+}
+
+// SearchByValue returns the stored element equal to val.
+func (sl *LazySkipList[T]) SearchByValue(val T) (T, bool) {
+	var hierarchy, succs [MaxLevelCap + 1]*lazyNode[T]
+	if found := sl.find(val, &hierarchy, &succs); found != -1 {
+		node := succs[found]
+		if node.fullyLinked.Load() && !node.marked.Load() {
+			return node.val, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Range iterates over the elements in ascending order. If fn returns false,
+// iteration stops. Like ConcurrentSkipList.Range it is weakly consistent:
+// elements added or deleted during the iteration may or may not be visited.
+func (sl *LazySkipList[T]) Range(fn func(val T) bool) {
+	for curr := sl.head.next[0].Load(); curr != nil; curr = curr.next[0].Load() {
+		if curr.fullyLinked.Load() && !curr.marked.Load() && !fn(curr.val) {
+			return
+		}
+	}
+}
+
+// Len returns the number of elements in the list. It is exact when no Add or
+// Delete is in progress and approximate otherwise.
+func (sl *LazySkipList[T]) Len() int {
+	return int(sl.length.Load())
+}
+
+// IsEmpty reports whether the list has no elements.
+func (sl *LazySkipList[T]) IsEmpty() bool {
+	empty := true
+	sl.Range(func(T) bool {
+		empty = false
+		return false
+	})
+	return empty
+}
@@ -0,0 +1,140 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// LazySkipList helpers
+// ------------------------------------------------------------
+
+func collectLazy(sl *LazySkipList[int]) []int {
+	var vals []int
+	sl.Range(func(v int) bool {
+		vals = append(vals, v)
+		return true
+	})
+	return vals
+}
+
+// ------------------------------------------------------------
+// LazySkipList tests
+// ------------------------------------------------------------
+
+func TestLazySkipList_Sequential(t *testing.T) {
+	sl := NewLazySkipList[int]()
+	if !sl.IsEmpty() {
+		t.Fatal("new list should be empty")
+	}
+
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		if !sl.Add(v) {
+			t.Fatalf("Add(%d) should succeed", v)
+		}
+	}
+	if sl.Add(7) {
+		t.Fatal("adding a duplicate should report false")
+	}
+	if !sl.Delete(1) || sl.Delete(1) || sl.Delete(4) {
+		t.Fatal("Delete should succeed once for present values only")
+	}
+
+	if got := collectLazy(sl); !slicesEqual(got, []int{3, 5, 7, 9}) {
+		t.Fatalf("unexpected contents: %v", got)
+	}
+	if sl.Len() != 4 || !sl.Contains(9) || sl.Contains(1) {
+		t.Fatalf("Len or Contains disagree with the contents, Len=%d", sl.Len())
+	}
+}
+
+func TestLazySkipList_ContendedAddAndDelete(t *testing.T) {
+	sl := NewLazySkipList[int]()
+	const goroutines, values = 8, 500
+
+	var added atomic.Int64
+	runParallel(goroutines, func(int) {
+		for v := 0; v < values; v++ {
+			if sl.Add(v) {
+				added.Add(1)
+			}
+		}
+	})
+	if added.Load() != values || sl.Len() != values {
+		t.Fatalf("expected %d successful adds, got %d (Len %d)", values, added.Load(), sl.Len())
+	}
+
+	var deleted atomic.Int64
+	runParallel(goroutines, func(int) {
+		for v := values - 1; v >= 0; v-- {
+			if sl.Delete(v) {
+				deleted.Add(1)
+			}
+		}
+	})
+	if deleted.Load() != values || !sl.IsEmpty() || sl.Len() != 0 {
+		t.Fatalf("expected %d successful deletes, got %d (Len %d)", values, deleted.Load(), sl.Len())
+	}
+}
+
+func TestLazySkipList_MatchesSequentialModel(t *testing.T) {
+	sl := NewLazySkipList[int]()
+	const goroutines, ops = 8, 3000
+
+	// each goroutine owns the values congruent to g, but neighbouring values
+	// belong to different goroutines, so their writes share predecessors
+	models := make([]map[int]bool, goroutines)
+	runParallel(goroutines, func(g int) {
+		rng := rand.New(rand.NewSource(int64(g)))
+		model := map[int]bool{}
+		for i := 0; i < ops; i++ {
+			v := rng.Intn(100)*goroutines + g
+			if rng.Intn(3) == 0 {
+				if sl.Delete(v) != model[v] {
+					t.Errorf("Delete(%d) disagrees with the model", v)
+				}
+				delete(model, v)
+			} else {
+				if sl.Add(v) == model[v] {
+					t.Errorf("Add(%d) disagrees with the model", v)
+				}
+				model[v] = true
+			}
+		}
+		models[g] = model
+	})
+
+	want := 0
+	for _, model := range models {
+		want += len(model)
+		for v := range model {
+			if !sl.Contains(v) {
+				t.Fatalf("value %d should be present", v)
+			}
+		}
+	}
+
+	got := collectLazy(sl)
+	if len(got) != want || sl.Len() != want {
+		t.Fatalf("expected %d values, Range saw %d and Len is %d", want, len(got), sl.Len())
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1] >= got[i] {
+			t.Fatalf("Range out of order: %d after %d", got[i], got[i-1])
+		}
+	}
+
+	// every level must be an ordered sublist of the level below it
+	for level := MaxLevelCap; level > 0; level-- {
+		below := map[*lazyNode[int]]bool{}
+		for n := sl.head.next[level-1].Load(); n != nil; n = n.next[level-1].Load() {
+			below[n] = true
+		}
+		for n := sl.head.next[level].Load(); n != nil; n = n.next[level].Load() {
+			if !below[n] {
+				t.Fatalf("node %d is linked at level %d but not at level %d", n.val, level, level-1)
+			}
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package lsm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	skiplist "github.com/anchor54/SkipList"
+)
+
+// run is an immutable sorted-table file produced by a flush or a compaction.
+type run struct {
+	id    uint64
+	tier  int
+	f     *os.File
+	table *skiplist.Table[[]byte, entry]
+}
+
+func openRun(path string, id uint64, tier int) (*run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	table, err := skiplist.OpenTableFunc[[]byte, entry](f, info.Size(), bytes.Compare, nil, entryCodec{})
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("lsm: opening %s: %w", path, err)
+	}
+	return &run{id: id, tier: tier, f: f, table: table}, nil
+}
+
+// sortRuns orders runs newest first: lower tiers are newer, and within a tier a
+// run with a higher id is newer.
+func sortRuns(runs []*run) {
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].tier != runs[j].tier {
+			return runs[i].tier < runs[j].tier
+		}
+		return runs[i].id > runs[j].id
+	})
+}
+
+// writeRun writes a run with write and opens it. The run only becomes part of
+// the DB once it is listed in the manifest.
+func (db *DB) writeRun(id uint64, tier int, write func(w io.Writer) error) (*run, error) {
+	path := db.path(id, ".sst")
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return openRun(path, id, tier)
+}
+
+// flusher flushes immutable memtables until the DB is closed or fails. It runs
+// apart from the compactor, so a long merge does not hold up the flush that
+// writers waiting in makeRoom need.
+func (db *DB) flusher() {
+	defer db.workers.Done()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for db.err == nil {
+		if db.imm != nil {
+			db.flush()
+			continue
+		}
+		if db.closed {
+			return
+		}
+		db.cond.Wait()
+	}
+}
+
+// compactor compacts tiers until the DB is closed or fails.
+func (db *DB) compactor() {
+	defer db.workers.Done()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for db.err == nil && !db.closed {
+		if tier := db.compactionTier(); tier >= 0 {
+			db.compact(tier)
+			continue
+		}
+		db.cond.Wait()
+	}
+}
+
+// flush writes the immutable memtable to a tier-0 run. It is called with mu
+// held and releases it while writing.
+func (db *DB) flush() {
+	imm := db.imm
+
+	// nothing writes to imm, so it can be read without the lock
+	db.mu.Unlock()
+	r, err := db.writeRun(imm.id, 0, imm.m.WriteSortedTable)
+	db.mu.Lock()
+	if err != nil {
+		db.fail(err)
+		return
+	}
+
+	runs := append([]*run{r}, db.runs...)
+	if err := writeManifest(db.dir, runs); err != nil {
+		db.fail(err)
+		return
+	}
+	db.runs, db.imm = runs, nil
+	imm.log.close()
+	os.Remove(db.path(imm.id, ".log"))
+	db.cond.Broadcast()
+}
+
+// compactionTier returns the lowest tier holding CompactionTrigger runs, or -1.
+func (db *DB) compactionTier() int {
+	counts := map[int]int{}
+	lowest := -1
+	for _, r := range db.runs {
+		counts[r.tier]++
+		if counts[r.tier] >= db.opts.CompactionTrigger && (lowest < 0 || r.tier < lowest) {
+			lowest = r.tier
+		}
+	}
+	return lowest
+}
+
+// compact merges every run of tier into one run of the next tier. It is called
+// with mu held and releases it while merging. Tombstones are dropped when no
+// older run remains that they could be hiding values in.
+func (db *DB) compact(tier int) {
+	var inputs []*run
+	isInput := map[*run]bool{}
+	for _, r := range db.runs {
+		if r.tier == tier {
+			inputs = append(inputs, r)
+			isInput[r] = true
+		}
+	}
+	// flushes only add runs in front, so nothing older than the inputs can
+	// appear while the lock is released, and the inputs stay contiguous
+	bottom := db.runs[len(db.runs)-1] == inputs[len(inputs)-1]
+	id := db.allocID()
+	hook := db.mergeHook
+
+	db.mu.Unlock()
+	if hook != nil {
+		hook()
+	}
+	var written int
+	out, err := db.writeRun(id, tier+1, func(w io.Writer) error {
+		tw, err := skiplist.NewTableWriter[[]byte, entry](w, nil, entryCodec{})
+		if err != nil {
+			return err
+		}
+		sources := make([]skiplist.Iterator[[]byte, entry], len(inputs))
+		for i, r := range inputs {
+			sources[i] = r.table.NewIterator()
+		}
+		it := newMergingIterator(sources)
+		for it.First(); it.Valid(); it.Next() {
+			if bottom && it.Value().tombstone {
+				continue
+			}
+			if err := tw.Add(it.Key(), it.Value()); err != nil {
+				return err
+			}
+			written++
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return tw.Finish()
+	})
+	db.mu.Lock()
+	if err != nil {
+		db.fail(err)
+		return
+	}
+
+	// the inputs are contiguous; replace them with the output, or with nothing
+	// if every entry was a dropped tombstone
+	var runs []*run
+	for _, r := range db.runs {
+		switch {
+		case r == inputs[0] && written > 0:
+			runs = append(runs, out)
+		case isInput[r]:
+			// merged into out
+		default:
+			runs = append(runs, r)
+		}
+	}
+	if err := writeManifest(db.dir, runs); err != nil {
+		db.fail(err)
+		return
+	}
+	db.runs = runs
+
+	if written == 0 {
+		out.f.Close()
+		os.Remove(db.path(out.id, ".sst"))
+	}
+	for _, r := range inputs {
+		r.f.Close()
+		os.Remove(db.path(r.id, ".sst"))
+	}
+	db.cond.Broadcast()
+}
+
+// The manifest lists the live runs, one "id tier" line each, newest first. It
+// is replaced atomically, so a crash leaves either the old or the new list.
+const manifestName = "MANIFEST"
+
+type manifestEntry struct {
+	id   uint64
+	tier int
+}
+
+func readManifest(dir string) (map[uint64]manifestEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if os.IsNotExist(err) {
+		return map[uint64]manifestEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	live := map[uint64]manifestEntry{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var e manifestEntry
+		if _, err := fmt.Sscanf(line, "%d %d", &e.id, &e.tier); err != nil {
+			return nil, fmt.Errorf("lsm: corrupt manifest line %q", line)
+		}
+		live[e.id] = e
+	}
+	return live, nil
+}
+
+func writeManifest(dir string, runs []*run) error {
+	var buf bytes.Buffer
+	for _, r := range runs {
+		fmt.Fprintf(&buf, "%d %d\n", r.id, r.tier)
+	}
+
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf.Bytes())
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(dir, manifestName))
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
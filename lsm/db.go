@@ -0,0 +1,433 @@
+// Package lsm is a small embeddable ordered key-value store built as a
+// log-structured merge tree on top of the skiplist package.
+//
+// Writes go to a write-ahead log and a skip-list memtable. When the memtable
+// reaches Options.MemtableSize it becomes immutable and a background flusher
+// writes it to a sorted run: an immutable sorted-table file. Reads merge the
+// memtables and runs, newest first, with deletions recorded as tombstones
+// until compaction drops them.
+//
+// Runs are compacted size-tiered. Flushed runs start in tier 0; once a tier
+// holds Options.CompactionTrigger runs they are merged into one run of the
+// next tier. Runs are ordered by age and every tier is older than the one
+// below it, so merging a whole tier never reorders versions of a key.
+package lsm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	skiplist "github.com/anchor54/SkipList"
+)
+
+// ErrClosed is returned by operations on a closed DB.
+var ErrClosed = errors.New("lsm: database is closed")
+
+// Options configures a DB. Zero fields take their defaults.
+type Options struct {
+	// MemtableSize is the approximate number of bytes of keys and values a
+	// memtable holds before it is flushed. The default is 4 MiB.
+	MemtableSize int
+	// CompactionTrigger is the number of runs in a tier that triggers their
+	// compaction into the next tier. The default is 4.
+	CompactionTrigger int
+	// SyncWrites fsyncs the write-ahead log after every write. Without it, a
+	// write survives a process crash but may be lost in a power failure.
+	SyncWrites bool
+}
+
+const (
+	defaultMemtableSize      = 4 << 20
+	defaultCompactionTrigger = 4
+	// entryOverhead approximates the memory a memtable entry costs beyond its
+	// key and value.
+	entryOverhead = 64
+)
+
+// entry is what a memtable or run stores for a key: a value or a tombstone.
+type entry struct {
+	value     []byte
+	tombstone bool
+}
+
+// entryCodec encodes an entry as a kind byte followed by the value.
+type entryCodec struct{}
+
+func (entryCodec) Encode(e entry) ([]byte, error) {
+	kind := byte(kindPut)
+	if e.tombstone {
+		kind = kindDelete
+	}
+	return append([]byte{kind}, e.value...), nil
+}
+
+func (entryCodec) Decode(data []byte) (entry, error) {
+	if len(data) == 0 || data[0] > kindDelete {
+		return entry{}, errors.New("invalid entry")
+	}
+	return entry{value: bytes.Clone(data[1:]), tombstone: data[0] == kindDelete}, nil
+}
+
+// memtable is a skip-list map with the log that makes it durable.
+type memtable struct {
+	id   uint64
+	m    *skiplist.SkipMap[[]byte, entry]
+	size int
+	log  *wal
+}
+
+func newMemtable(id uint64) *memtable {
+	// keys are copied by Put, so the map can borrow them
+	m := skiplist.NewBytesMap[entry](skiplist.BorrowKeys)
+	m.UseCodecs(nil, entryCodec{})
+	return &memtable{id: id, m: m}
+}
+
+func (mt *memtable) apply(kind byte, key, value []byte) {
+	mt.m.Set(key, entry{value: value, tombstone: kind == kindDelete})
+	mt.size += len(key) + len(value) + entryOverhead
+}
+
+// DB is an ordered key-value store. It is safe for concurrent use.
+type DB struct {
+	dir  string
+	opts Options
+
+	mu sync.RWMutex
+	// cond is broadcast when a flush or compaction finishes, when a background
+	// error occurs and when the DB is closed.
+	cond   *sync.Cond
+	mem    *memtable
+	imm    *memtable
+	runs   []*run // newest first
+	nextID uint64
+	// err is the first log or background error. The DB refuses writes after
+	// it, since they might not be durable.
+	err    error
+	closed bool
+	// workers counts the flush and compaction goroutines
+	workers sync.WaitGroup
+	// mergeHook, if set, is called by compactions while they merge without the
+	// lock. Tests use it to hold a compaction open.
+	mergeHook func()
+}
+
+// Open opens the database in dir, creating it if needed. Runs listed in the
+// manifest are opened, and logs of memtables that were never flushed are
+// replayed and flushed before Open returns.
+func Open(dir string, opts Options) (*DB, error) {
+	if opts.MemtableSize <= 0 {
+		opts.MemtableSize = defaultMemtableSize
+	}
+	if opts.CompactionTrigger < 2 {
+		opts.CompactionTrigger = defaultCompactionTrigger
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	db := &DB{dir: dir, opts: opts}
+	db.cond = sync.NewCond(&db.mu)
+	if err := db.recover(); err != nil {
+		db.closeFiles()
+		return nil, err
+	}
+
+	db.mem = newMemtable(db.allocID())
+	log, err := createWAL(db.path(db.mem.id, ".log"), opts.SyncWrites)
+	if err != nil {
+		db.closeFiles()
+		return nil, err
+	}
+	db.mem.log = log
+
+	db.workers.Add(2)
+	go db.flusher()
+	go db.compactor()
+	return db, nil
+}
+
+// recover loads the runs listed in the manifest, removes files left behind by
+// interrupted flushes and compactions, and flushes the contents of leftover
+// logs into a new run.
+func (db *DB) recover() error {
+	live, err := readManifest(db.dir)
+	if err != nil {
+		return err
+	}
+	for _, r := range live {
+		db.nextID = max(db.nextID, r.id+1)
+	}
+
+	names, err := os.ReadDir(db.dir)
+	if err != nil {
+		return err
+	}
+	var logs []uint64
+	for _, de := range names {
+		id, ext, ok := parseFileName(de.Name())
+		if !ok {
+			continue
+		}
+		db.nextID = max(db.nextID, id+1)
+		_, listed := live[id]
+		switch {
+		case ext == ".log" && !listed:
+			logs = append(logs, id)
+		case ext == ".log", ext == ".sst" && !listed:
+			// a flushed log, or the output of an interrupted flush or compaction
+			if err := os.Remove(filepath.Join(db.dir, de.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, r := range live {
+		opened, err := openRun(db.path(r.id, ".sst"), r.id, r.tier)
+		if err != nil {
+			return err
+		}
+		db.runs = append(db.runs, opened)
+	}
+	sortRuns(db.runs)
+
+	if len(logs) == 0 {
+		return nil
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i] < logs[j] })
+	mt := newMemtable(db.allocID())
+	for _, id := range logs {
+		if err := replayWAL(db.path(id, ".log"), mt.apply); err != nil {
+			return err
+		}
+	}
+	if mt.m.Len() > 0 {
+		r, err := db.writeRun(mt.id, 0, mt.m.WriteSortedTable)
+		if err != nil {
+			return err
+		}
+		runs := append([]*run{r}, db.runs...)
+		if err := writeManifest(db.dir, runs); err != nil {
+			return err
+		}
+		db.runs = runs
+	}
+	for _, id := range logs {
+		if err := os.Remove(db.path(id, ".log")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Put sets the value of key. The DB keeps its own copies of key and value.
+func (db *DB) Put(key, value []byte) error {
+	return db.write(kindPut, key, value)
+}
+
+// Delete removes key. It records a tombstone that hides older values of key
+// until compaction drops them.
+func (db *DB) Delete(key []byte) error {
+	return db.write(kindDelete, key, nil)
+}
+
+func (db *DB) write(kind byte, key, value []byte) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := db.makeRoom(); err != nil {
+		return err
+	}
+	key, value = bytes.Clone(key), bytes.Clone(value)
+	if err := db.mem.log.append(kind, key, value); err != nil {
+		db.fail(err)
+		return err
+	}
+	db.mem.apply(kind, key, value)
+	return nil
+}
+
+// makeRoom swaps a full memtable for an empty one, waiting first for the
+// previous immutable memtable to be flushed.
+func (db *DB) makeRoom() error {
+	for {
+		switch {
+		case db.closed:
+			return ErrClosed
+		case db.err != nil:
+			return db.err
+		case db.mem.size < db.opts.MemtableSize:
+			return nil
+		case db.imm != nil:
+			db.cond.Wait()
+			continue
+		}
+
+		mem := newMemtable(db.allocID())
+		log, err := createWAL(db.path(mem.id, ".log"), db.opts.SyncWrites)
+		if err != nil {
+			db.fail(err)
+			return err
+		}
+		mem.log = log
+		db.imm, db.mem = db.mem, mem
+		db.cond.Broadcast()
+		return nil
+	}
+}
+
+// Get returns the value of key.
+func (db *DB) Get(key []byte) ([]byte, bool, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return nil, false, ErrClosed
+	}
+
+	for _, mt := range []*memtable{db.mem, db.imm} {
+		if mt == nil {
+			continue
+		}
+		if e, found := mt.m.Get(key); found {
+			return visible(e)
+		}
+	}
+	for _, r := range db.runs {
+		e, found, err := r.table.Get(key)
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			return visible(e)
+		}
+	}
+	return nil, false, nil
+}
+
+func visible(e entry) ([]byte, bool, error) {
+	if e.tombstone {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+// Scan calls fn for every key in [start, end) in ascending order, with the
+// newest value of each key. A nil start or end leaves that side unbounded.
+// If fn returns false, the scan stops.
+//
+// Scan sees a consistent state of the DB: writes wait until it returns, so fn
+// must not call methods on db.
+func (db *DB) Scan(start, end []byte, fn func(key, value []byte) bool) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+
+	it := db.newMergingIterator()
+	if start == nil {
+		it.First()
+	} else {
+		it.Seek(start)
+	}
+	for ; it.Valid(); it.Next() {
+		if end != nil && bytes.Compare(it.Key(), end) >= 0 {
+			break
+		}
+		if e := it.Value(); !e.tombstone && !fn(it.Key(), e.value) {
+			break
+		}
+	}
+	return it.Err()
+}
+
+// newMergingIterator merges the memtables and runs, newest first. The caller
+// must hold mu.
+func (db *DB) newMergingIterator() *mergingIterator {
+	var sources []skiplist.Iterator[[]byte, entry]
+	for _, mt := range []*memtable{db.mem, db.imm} {
+		if mt != nil {
+			sources = append(sources, mt.m.NewIterator())
+		}
+	}
+	for _, r := range db.runs {
+		sources = append(sources, r.table.NewIterator())
+	}
+	return newMergingIterator(sources)
+}
+
+// Close waits for the background workers, flushing the memtable to a run, and
+// closes all files. The DB must not be used afterwards.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return ErrClosed
+	}
+	db.closed = true
+	if db.err == nil && db.mem.m.Len() > 0 {
+		for db.imm != nil && db.err == nil {
+			db.cond.Wait()
+		}
+		// if a failure stopped the wait, imm was never flushed; keep both
+		// memtables so closeFiles closes both logs
+		if db.imm == nil {
+			db.imm, db.mem = db.mem, nil
+		}
+	}
+	db.cond.Broadcast()
+	db.mu.Unlock()
+
+	db.workers.Wait()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.closeFiles()
+	return db.err
+}
+
+func (db *DB) closeFiles() {
+	for _, mt := range []*memtable{db.mem, db.imm} {
+		if mt != nil && mt.log != nil {
+			mt.log.close()
+		}
+	}
+	for _, r := range db.runs {
+		r.f.Close()
+	}
+}
+
+// fail records the first error that leaves the DB unable to take writes.
+func (db *DB) fail(err error) {
+	if db.err == nil {
+		db.err = err
+	}
+	db.cond.Broadcast()
+}
+
+func (db *DB) allocID() uint64 {
+	id := db.nextID
+	db.nextID++
+	return id
+}
+
+func (db *DB) path(id uint64, ext string) string {
+	return filepath.Join(db.dir, fmt.Sprintf("%06d%s", id, ext))
+}
+
+// parseFileName splits a log or run file name into its id and extension.
+func parseFileName(name string) (uint64, string, bool) {
+	ext := filepath.Ext(name)
+	if ext != ".log" && ext != ".sst" {
+		return 0, "", false
+	}
+	id, err := strconv.ParseUint(strings.TrimSuffix(name, ext), 10, 64)
+	return id, ext, err == nil
+}
@@ -0,0 +1,432 @@
+package lsm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	skiplist "github.com/anchor54/SkipList"
+	"github.com/anchor54/SkipList/internal/record"
+)
+
+// ------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------
+
+func openDB(t *testing.T, dir string, opts Options) *DB {
+	t.Helper()
+	db, err := Open(dir, opts)
+	if err != nil {
+		t.Fatalf("Open returned %v", err)
+	}
+	return db
+}
+
+// waitIdle blocks until no flush or compaction is pending.
+func waitIdle(db *DB) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for db.err == nil && (db.imm != nil || db.compactionTier() >= 0) {
+		db.cond.Wait()
+	}
+}
+
+func key(i int) []byte {
+	return []byte(fmt.Sprintf("key-%05d", i))
+}
+
+func mustGet(t *testing.T, db *DB, k []byte) (string, bool) {
+	t.Helper()
+	value, found, err := db.Get(k)
+	if err != nil {
+		t.Fatalf("Get(%s) returned %v", k, err)
+	}
+	return string(value), found
+}
+
+func scanAll(t *testing.T, db *DB, start, end []byte) []string {
+	t.Helper()
+	var pairs []string
+	err := db.Scan(start, end, func(k, v []byte) bool {
+		pairs = append(pairs, string(k)+"="+string(v))
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Scan returned %v", err)
+	}
+	return pairs
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ------------------------------------------------------------
+// Tests
+// ------------------------------------------------------------
+
+func TestDB_PutGetDelete(t *testing.T) {
+	db := openDB(t, t.TempDir(), Options{})
+	defer db.Close()
+
+	db.Put([]byte("a"), []byte("1"))
+	db.Put([]byte("b"), []byte("2"))
+	db.Put([]byte("a"), []byte("3"))
+	db.Delete([]byte("b"))
+
+	if v, found := mustGet(t, db, []byte("a")); !found || v != "3" {
+		t.Fatalf("expected a=3, got %q %v", v, found)
+	}
+	if _, found := mustGet(t, db, []byte("b")); found {
+		t.Fatal("deleted key should not be found")
+	}
+}
+
+func TestDB_FlushAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir, Options{MemtableSize: 2048, CompactionTrigger: 100})
+	for i := 0; i < 500; i++ {
+		if err := db.Put(key(i), []byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf("Put returned %v", err)
+		}
+	}
+	waitIdle(db)
+	if len(db.runs) < 5 {
+		t.Fatalf("small memtables should have been flushed to several runs, got %d", len(db.runs))
+	}
+	if v, found := mustGet(t, db, key(7)); !found || v != "7" {
+		t.Fatalf("expected key 7 from a run, got %q %v", v, found)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	if err := db.Put(key(1), nil); err != ErrClosed {
+		t.Fatalf("Put after Close should return ErrClosed, got %v", err)
+	}
+
+	db = openDB(t, dir, Options{MemtableSize: 2048, CompactionTrigger: 100})
+	defer db.Close()
+	for _, i := range []int{0, 250, 499} {
+		if v, found := mustGet(t, db, key(i)); !found || v != fmt.Sprint(i) {
+			t.Fatalf("key %d lost across reopen: %q %v", i, v, found)
+		}
+	}
+	if n := len(scanAll(t, db, nil, nil)); n != 500 {
+		t.Fatalf("expected 500 keys after reopen, got %d", n)
+	}
+}
+
+func TestDB_ScanMergesNewestVersions(t *testing.T) {
+	db := openDB(t, t.TempDir(), Options{MemtableSize: 1, CompactionTrigger: 100})
+	defer db.Close()
+
+	// every write fills the memtable, so each version lands in its own run
+	db.Put([]byte("a"), []byte("old"))
+	db.Put([]byte("b"), []byte("old"))
+	db.Put([]byte("c"), []byte("old"))
+	db.Put([]byte("a"), []byte("new"))
+	db.Delete([]byte("b"))
+	db.Put([]byte("d"), []byte("new"))
+	waitIdle(db)
+
+	if got := scanAll(t, db, nil, nil); !equalStrings(got, []string{"a=new", "c=old", "d=new"}) {
+		t.Fatalf("unexpected scan %v", got)
+	}
+	if got := scanAll(t, db, []byte("b"), []byte("d")); !equalStrings(got, []string{"c=old"}) {
+		t.Fatalf("unexpected bounded scan %v", got)
+	}
+
+	var first []string
+	db.Scan(nil, nil, func(k, v []byte) bool {
+		first = append(first, string(k))
+		return false
+	})
+	if !equalStrings(first, []string{"a"}) {
+		t.Fatalf("Scan should stop when fn returns false, got %v", first)
+	}
+}
+
+func TestDB_CompactionMergesTiersAndDropsTombstones(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir, Options{MemtableSize: 512, CompactionTrigger: 2})
+	for i := 0; i < 400; i++ {
+		db.Put(key(i), []byte("v"))
+	}
+	for i := 0; i < 400; i += 2 {
+		db.Delete(key(i))
+	}
+	db.Close()
+
+	// reopening leaves no memtable behind; wait for the last compactions
+	db = openDB(t, dir, Options{MemtableSize: 512, CompactionTrigger: 2})
+	defer db.Close()
+	waitIdle(db)
+
+	tiers := map[int]int{}
+	for _, r := range db.runs {
+		tiers[r.tier]++
+	}
+	for tier, n := range tiers {
+		if n >= 2 {
+			t.Fatalf("tier %d still holds %d runs after compaction", tier, n)
+		}
+	}
+	if len(db.runs) == 0 || db.runs[len(db.runs)-1].tier == 0 {
+		t.Fatalf("expected compacted runs above tier 0, got %d runs", len(db.runs))
+	}
+
+	// the bottom run holds no tombstones
+	bottom := db.runs[len(db.runs)-1].table.NewIterator()
+	for bottom.First(); bottom.Valid(); bottom.Next() {
+		if bottom.Value().tombstone {
+			t.Fatalf("bottom run still holds the tombstone of %s", bottom.Key())
+		}
+	}
+	if got := scanAll(t, db, nil, nil); len(got) != 200 || got[0] != "key-00001=v" {
+		t.Fatalf("expected the 200 odd keys, got %d starting %v", len(got), got[:min(len(got), 1)])
+	}
+
+	// compacted inputs are removed from disk
+	files, _ := filepath.Glob(filepath.Join(dir, "*.sst"))
+	if len(files) != len(db.runs) {
+		t.Fatalf("%d run files on disk for %d live runs", len(files), len(db.runs))
+	}
+}
+
+func TestDB_RecoversUnflushedWritesFromLog(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir, Options{})
+	db.Put([]byte("x"), []byte("1"))
+	db.Put([]byte("y"), []byte("2"))
+	db.Delete([]byte("x"))
+
+	// simulate a crash: abandon db without closing it, and tear the log tail
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(logs) != 1 {
+		t.Fatalf("expected one log, found %v", logs)
+	}
+	f, _ := os.OpenFile(logs[0], os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte{42, 0, 0, 0, 1})
+	f.Close()
+
+	db = openDB(t, dir, Options{})
+	defer db.Close()
+	if _, found := mustGet(t, db, []byte("x")); found {
+		t.Fatal("x was deleted before the crash")
+	}
+	if v, found := mustGet(t, db, []byte("y")); !found || v != "2" {
+		t.Fatalf("expected y=2 after recovery, got %q %v", v, found)
+	}
+}
+
+func TestDB_OpenFailsOnCorruptLogRecord(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir, Options{})
+	db.Put([]byte("x"), []byte("1"))
+	db.Put([]byte("y"), []byte("2"))
+	db.Put([]byte("z"), []byte("3"))
+
+	// damage the first record's payload; the records after it are intact, so
+	// this is corruption rather than a torn tail
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(logs) != 1 {
+		t.Fatalf("expected one log, found %v", logs)
+	}
+	data, err := os.ReadFile(logs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[record.HeaderSize+3] ^= 0xFF
+	if err := os.WriteFile(logs[0], data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(dir, Options{}); !errors.Is(err, skiplist.ErrCorruptLog) {
+		t.Fatalf("Open should fail with ErrCorruptLog, got %v", err)
+	}
+	if got, _ := os.ReadFile(logs[0]); len(got) != len(data) {
+		t.Fatalf("the corrupt log was rewritten: %d bytes, was %d", len(got), len(data))
+	}
+}
+
+func TestDB_OpenFailsOnCorruptLogLength(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir, Options{})
+	for i := 0; i < 10; i++ {
+		db.Put([]byte(fmt.Sprintf("k%d", i)), []byte("v"))
+	}
+
+	// give the second record a length past the end of the log; the records
+	// after it must not be dropped as if they were a torn tail
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(logs) != 1 {
+		t.Fatalf("expected one log, found %v", logs)
+	}
+	data, err := os.ReadFile(logs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first payload is a kind byte, a one-byte key length, "k0" and "v"
+	second := record.HeaderSize + 5
+	binary.LittleEndian.PutUint32(data[second:], 1<<20)
+	if err := os.WriteFile(logs[0], data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(dir, Options{}); !errors.Is(err, skiplist.ErrCorruptLog) {
+		t.Fatalf("Open should fail with ErrCorruptLog, got %v", err)
+	}
+	if got, _ := os.ReadFile(logs[0]); !bytes.Equal(got, data) {
+		t.Fatal("the corrupt log was modified")
+	}
+}
+
+func TestDB_WritesProceedDuringSlowCompaction(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir, Options{MemtableSize: 1 << 10, CompactionTrigger: 2})
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	db.mu.Lock()
+	db.mergeHook = func() {
+		once.Do(func() { close(started) })
+		<-release
+	}
+	db.mu.Unlock()
+
+	// fill memtables until the first compaction is held open
+	i := 0
+	for compacting := false; !compacting; i++ {
+		if err := db.Put(key(i), []byte("value")); err != nil {
+			t.Fatalf("Put returned %v", err)
+		}
+		select {
+		case <-started:
+			compacting = true
+		default:
+		}
+	}
+
+	// several more memtables' worth of writes need flushes, which must not
+	// wait for the merge
+	done := make(chan error, 1)
+	go func() {
+		for j := i; j < i+500; j++ {
+			if err := db.Put(key(j), []byte("value")); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Put returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		close(release)
+		t.Fatal("writes stalled behind the compaction")
+	}
+
+	close(release)
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	db = openDB(t, dir, Options{})
+	defer db.Close()
+	for j := 0; j < i+500; j++ {
+		if _, found := mustGet(t, db, key(j)); !found {
+			t.Fatalf("%s was lost", key(j))
+		}
+	}
+}
+
+func TestDB_MatchesModelAcrossReopens(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{MemtableSize: 1024, CompactionTrigger: 3}
+	rng := rand.New(rand.NewSource(45))
+	model := map[string]string{}
+
+	for round := 0; round < 5; round++ {
+		db := openDB(t, dir, opts)
+		for i := 0; i < 400; i++ {
+			k := key(rng.Intn(300))
+			if rng.Intn(3) == 0 {
+				db.Delete(k)
+				delete(model, string(k))
+			} else {
+				v := fmt.Sprint(round, "-", i)
+				db.Put(k, []byte(v))
+				model[string(k)] = v
+			}
+		}
+
+		var want []string
+		for k, v := range model {
+			want = append(want, k+"="+v)
+		}
+		sort.Strings(want)
+		if got := scanAll(t, db, nil, nil); !equalStrings(got, want) {
+			t.Fatalf("round %d: scan has %d keys, model has %d", round, len(got), len(want))
+		}
+		if err := db.Close(); err != nil {
+			t.Fatalf("Close returned %v", err)
+		}
+	}
+}
+
+func TestDB_ConcurrentReadersAndWriters(t *testing.T) {
+	db := openDB(t, t.TempDir(), Options{MemtableSize: 4096, CompactionTrigger: 2})
+	defer db.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 300; i++ {
+				if err := db.Put(key(w*1000+i), []byte("v")); err != nil {
+					t.Errorf("Put returned %v", err)
+					return
+				}
+			}
+		}(w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				prev := ""
+				db.Scan(nil, nil, func(k, _ []byte) bool {
+					if string(k) <= prev {
+						t.Errorf("scan out of order: %s after %s", k, prev)
+					}
+					prev = string(k)
+					return true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	waitIdle(db)
+	if n := len(scanAll(t, db, nil, nil)); n != 1200 {
+		t.Fatalf("expected 1200 keys, got %d", n)
+	}
+}
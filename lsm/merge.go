@@ -0,0 +1,88 @@
+package lsm
+
+import (
+	"bytes"
+
+	skiplist "github.com/anchor54/SkipList"
+)
+
+// mergingIterator merges sources ordered newest first into one ascending
+// stream of keys. When several sources hold a key, the newest wins and the
+// older versions are skipped. Tombstones are yielded like any other entry;
+// Scan hides them and compaction decides whether to keep them.
+type mergingIterator struct {
+	sources []skiplist.Iterator[[]byte, entry]
+	// cur is the index of the source positioned at the current key, or -1.
+	cur int
+}
+
+func newMergingIterator(sources []skiplist.Iterator[[]byte, entry]) *mergingIterator {
+	return &mergingIterator{sources: sources, cur: -1}
+}
+
+// First positions the iterator at the smallest key.
+func (it *mergingIterator) First() {
+	for _, src := range it.sources {
+		src.First()
+	}
+	it.settle()
+}
+
+// Seek positions the iterator at the first key greater than or equal to key.
+func (it *mergingIterator) Seek(key []byte) {
+	for _, src := range it.sources {
+		src.Seek(key)
+	}
+	it.settle()
+}
+
+// Next moves past the current key in every source that holds it.
+func (it *mergingIterator) Next() {
+	if it.cur < 0 {
+		return
+	}
+	key := it.Key()
+	for _, src := range it.sources {
+		if src.Valid() && bytes.Equal(src.Key(), key) {
+			src.Next()
+		}
+	}
+	it.settle()
+}
+
+func (it *mergingIterator) Valid() bool {
+	return it.cur >= 0
+}
+
+func (it *mergingIterator) Key() []byte {
+	return it.sources[it.cur].Key()
+}
+
+func (it *mergingIterator) Value() entry {
+	return it.sources[it.cur].Value()
+}
+
+// Err returns the first read error of a source.
+func (it *mergingIterator) Err() error {
+	for _, src := range it.sources {
+		if e, ok := src.(interface{ Err() error }); ok && e.Err() != nil {
+			return e.Err()
+		}
+	}
+	return nil
+}
+
+// settle picks the source with the smallest key, preferring the newest on
+// ties. The iterator is invalid once any source fails, so a read error never
+// passes for the end of the data.
+func (it *mergingIterator) settle() {
+	it.cur = -1
+	if it.Err() != nil {
+		return
+	}
+	for i, src := range it.sources {
+		if src.Valid() && (it.cur < 0 || bytes.Compare(src.Key(), it.Key()) < 0) {
+			it.cur = i
+		}
+	}
+}
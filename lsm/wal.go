@@ -0,0 +1,85 @@
+package lsm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	skiplist "github.com/anchor54/SkipList"
+	"github.com/anchor54/SkipList/internal/record"
+)
+
+// The log is a sequence of checksummed records. Each payload is a kind byte,
+// the key length as a uvarint, the key and the value.
+const (
+	kindPut    = 0
+	kindDelete = 1
+)
+
+// wal is the write-ahead log of one memtable. It is deleted once the memtable
+// has been flushed to a run.
+type wal struct {
+	f    *os.File
+	sync bool
+}
+
+func createWAL(path string, sync bool) (*wal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &wal{f: f, sync: sync}, nil
+}
+
+func (w *wal) append(kind byte, key, value []byte) error {
+	payload := make([]byte, 0, 1+binary.MaxVarintLen64+len(key)+len(value))
+	payload = append(payload, kind)
+	payload = binary.AppendUvarint(payload, uint64(len(key)))
+	payload = append(payload, key...)
+	payload = append(payload, value...)
+
+	if _, err := w.f.Write(record.Append(nil, payload)); err != nil {
+		return err
+	}
+	if w.sync {
+		return w.f.Sync()
+	}
+	return nil
+}
+
+func (w *wal) close() error {
+	return w.f.Close()
+}
+
+// replayWAL calls fn for every record of the log at path. Replay stops at a
+// torn record at the tail, which a crash can leave behind, and fails with an
+// error wrapping skiplist.ErrCorruptLog at a damaged record anywhere else. A
+// damaged length counts as such a record, since record headers carry their
+// own checksum, so it cannot make the rest of the log look torn.
+func replayWAL(path string, fn func(kind byte, key, value []byte)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rr := record.NewReader(f)
+	for {
+		payload, err := rr.Next()
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lsm: replaying %s: %w", path, err)
+		}
+
+		keyLen, n := binary.Uvarint(payload[1:])
+		if n <= 0 || keyLen > uint64(len(payload)-1-n) {
+			return fmt.Errorf("lsm: replaying %s: %w at offset %d: malformed payload", path, skiplist.ErrCorruptLog, rr.Offset())
+		}
+		key := payload[1+n : 1+n+int(keyLen)]
+		fn(payload[0], key, payload[1+n+int(keyLen):])
+	}
+}
@@ -0,0 +1,511 @@
+package skiplist
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"unsafe"
+)
+
+// ErrBadMappedFile is returned by OpenMapped when a file is truncated, fails
+// its checksum or describes an inconsistent list.
+var ErrBadMappedFile = errors.New("skiplist: bad mapped file")
+
+// A mapped file is read in place, so every field has a fixed width and all
+// integers are little-endian.
+//
+//	header  magic "SKMP", version (1), maxLevel (1), reserved (2), count (8),
+//	        the head's tower as MaxLevelCap+1 (next offset, span) pairs (16 each)
+//	nodes   in list order: height (1), key length (4), the tower as height
+//	        (next offset, span) pairs, the encoded key
+//	crc32   4 bytes, Castagnoli, over everything before it
+//
+// Offsets are from the start of the file, and offset 0 is nil.
+const (
+	mappedMagic      = "SKMP"
+	mappedVersion    = 1
+	mappedTowerStart = 16
+	mappedHeaderSize = mappedTowerStart + (MaxLevelCap+1)*mappedLevelSize
+	mappedLevelSize  = 16
+	mappedNodeHeader = 1 + 4
+)
+
+// WriteMapped writes the list in the layout read by OpenMapped. Towers keep
+// their heights and spans, so the mapped list has the same search paths and
+// rank queries work in place. Elements are encoded with the list's codec, as
+// for MarshalBinary.
+func (sl *SkipList[T]) WriteMapped(w io.Writer) error {
+	codec, err := sl.elementCodec()
+	if err != nil {
+		return err
+	}
+
+	// lay the nodes out first, so towers can point forward
+	keys := make([][]byte, 0, sl.length)
+	offsets := make(map[*Node[T]]uint64, sl.length)
+	off := uint64(mappedHeaderSize)
+	for node := sl.firstNode(); node != nil; node = node.levels[0].next {
+		data, err := codec.Encode(node.val)
+		if err != nil {
+			return err
+		}
+		if len(data) > math.MaxUint32 {
+			return fmt.Errorf("skiplist: element of %d bytes is too large to map", len(data))
+		}
+		keys = append(keys, data)
+		offsets[node] = off
+		off += uint64(mappedNodeHeader + len(node.levels)*mappedLevelSize + len(data))
+	}
+
+	crc := crc32.New(snapshotTable)
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+	buf := make([]byte, 0, mappedHeaderSize)
+
+	buf = append(buf, mappedMagic...)
+	buf = append(buf, mappedVersion, byte(sl.maxLevel), 0, 0)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(sl.length))
+	if sl.head == nil {
+		// a zero list: every head level spans past the end
+		for range MaxLevelCap + 1 {
+			buf = binary.LittleEndian.AppendUint64(buf, 0)
+			buf = binary.LittleEndian.AppendUint64(buf, 1)
+		}
+	} else {
+		buf = appendMappedTower(buf, sl.head.levels, offsets)
+	}
+	bw.Write(buf)
+
+	i := 0
+	for node := sl.firstNode(); node != nil; node = node.levels[0].next {
+		buf = append(buf[:0], byte(len(node.levels)))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(keys[i])))
+		buf = appendMappedTower(buf, node.levels, offsets)
+		buf = append(buf, keys[i]...)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		i++
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(binary.LittleEndian.AppendUint32(nil, crc.Sum32()))
+	return err
+}
+
+func appendMappedTower[T any](buf []byte, levels []level[T], offsets map[*Node[T]]uint64) []byte {
+	for _, l := range levels {
+		buf = binary.LittleEndian.AppendUint64(buf, offsets[l.next])
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(l.span))
+	}
+	return buf
+}
+
+// MappedSkipList is a read-only skip list queried in place from a file written
+// by WriteMapped. On unix systems the file is memory-mapped, so opening it
+// costs a checksum and a pass over the node headers, without decoding any
+// element, and lookups only touch the pages on their search path. Elsewhere
+// the file is read into memory.
+//
+// For the types accepted by OpenMapped, lookups and iteration do not allocate.
+// Strings returned by a list of a string type point into the mapping and must
+// not be used after Close.
+//
+// A MappedSkipList is safe for concurrent use until it is closed.
+type MappedSkipList[T any] struct {
+	data       []byte
+	end        int // start of the checksum
+	maxLevel   int
+	length     int
+	comparator Comparator[T]
+	codec      Codec[T]
+	decode     func([]byte) T
+}
+
+// OpenMapped opens a mapped skip list of an ordered type.
+func OpenMapped[T cmp.Ordered](path string) (*MappedSkipList[T], error) {
+	codec, err := codecOrBuiltIn[T](nil)
+	if err != nil {
+		return nil, err
+	}
+	return openMapped(path, cmp.Compare[T], codec, mappedDecoderFor[T]())
+}
+
+// OpenComparableMapped opens a mapped skip list of a type that implements the
+// Comparable interface, decoding elements with codec.
+func OpenComparableMapped[T Comparable[T]](path string, codec Codec[T]) (*MappedSkipList[T], error) {
+	if codec == nil {
+		return nil, ErrNoCodec
+	}
+	// an element that fails to decode reads as the zero value; Validate
+	// reports it
+	decode := func(data []byte) T {
+		val, _ := codec.Decode(data)
+		return val
+	}
+	return openMapped(path, func(a, b T) int { return a.Compare(b) }, codec, decode)
+}
+
+func openMapped[T any](path string, comparator Comparator[T], codec Codec[T], decode func([]byte) T) (*MappedSkipList[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < mappedHeaderSize+4 || info.Size() > math.MaxInt {
+		return nil, fmt.Errorf("%w: size %d", ErrBadMappedFile, info.Size())
+	}
+
+	data, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	m := &MappedSkipList[T]{data: data, comparator: comparator, codec: codec, decode: decode}
+	if err := m.validate(); err != nil {
+		unmapFile(data)
+		return nil, err
+	}
+	return m, nil
+}
+
+// validate checks the structure of the file once, so lookups can follow
+// offsets without bounds checks failing: every tower must point forward to a
+// node at least as tall as the level, with a span equal to the distance in
+// ranks. It walks the nodes in file order, which is list order, keeping only
+// the last tower seen at each level, and decodes no elements; the element
+// order is left to Validate.
+func (m *MappedSkipList[T]) validate() error {
+	data := m.data
+	m.end = len(data) - 4
+	if string(data[:4]) != mappedMagic || data[4] != mappedVersion {
+		return fmt.Errorf("%w: missing header", ErrBadMappedFile)
+	}
+	if crc32.Checksum(data[:m.end], snapshotTable) != binary.LittleEndian.Uint32(data[m.end:]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrBadMappedFile)
+	}
+	m.maxLevel = int(data[5])
+	count := binary.LittleEndian.Uint64(data[8:])
+	if m.maxLevel > MaxLevelCap || count > uint64(m.end) {
+		return fmt.Errorf("%w: corrupt header", ErrBadMappedFile)
+	}
+	m.length = int(count)
+
+	// pending[lvl] is the last tower seen at lvl and its rank; its link must
+	// lead to the next node tall enough to have lvl
+	type link struct {
+		from uint64
+		rank int
+	}
+	var pending [MaxLevelCap + 1]link
+	follow := func(from uint64, rank, height int) error {
+		for lvl := 0; lvl < height; lvl++ {
+			if m.next(from, lvl) != 0 && lvl > m.maxLevel {
+				return fmt.Errorf("%w: bad link at level %d from %d", ErrBadMappedFile, lvl, from)
+			}
+			pending[lvl] = link{from, rank}
+		}
+		return nil
+	}
+	if err := follow(0, 0, MaxLevelCap+1); err != nil {
+		return err
+	}
+
+	rank := 0
+	for off := mappedHeaderSize; off < m.end; {
+		if off+mappedNodeHeader > m.end {
+			return fmt.Errorf("%w: truncated node at %d", ErrBadMappedFile, off)
+		}
+		height := int(data[off])
+		keyLen := int(binary.LittleEndian.Uint32(data[off+1:]))
+		keyOff := off + mappedNodeHeader + height*mappedLevelSize
+		if height == 0 || height > m.maxLevel+1 || keyOff > m.end || keyLen > m.end-keyOff {
+			return fmt.Errorf("%w: corrupt node at %d", ErrBadMappedFile, off)
+		}
+		rank++
+		for lvl, p := range pending[:m.maxLevel+1] {
+			linked := m.next(p.from, lvl) == uint64(off)
+			if linked != (lvl < height) {
+				return fmt.Errorf("%w: bad link at level %d from %d", ErrBadMappedFile, lvl, p.from)
+			}
+			if linked && m.span(p.from, lvl) != rank-p.rank {
+				return fmt.Errorf("%w: span %d at level %d from %d, expected %d", ErrBadMappedFile, m.span(p.from, lvl), lvl, p.from, rank-p.rank)
+			}
+		}
+		if err := follow(uint64(off), rank, height); err != nil {
+			return err
+		}
+		off = keyOff + keyLen
+	}
+	if rank != m.length {
+		return fmt.Errorf("%w: %d nodes, header says %d", ErrBadMappedFile, rank, m.length)
+	}
+	for lvl, p := range pending {
+		if next, span := m.next(p.from, lvl), m.span(p.from, lvl); next != 0 || span != m.length+1-p.rank {
+			return fmt.Errorf("%w: bad link at level %d from %d", ErrBadMappedFile, lvl, p.from)
+		}
+	}
+	return nil
+}
+
+// Validate decodes every element and checks that they are in strictly
+// ascending order, which opening the list does not. It returns an error
+// wrapping ErrBadMappedFile that names the first bad element.
+//
+// Time Complexity: O(n)
+func (m *MappedSkipList[T]) Validate() error {
+	var prev T
+	for off := m.next(0, 0); off != 0; off = m.next(off, 0) {
+		val, err := m.codec.Decode(m.key(off))
+		if err != nil {
+			return fmt.Errorf("%w: node at %d: %v", ErrBadMappedFile, off, err)
+		}
+		if off != m.next(0, 0) && m.comparator(prev, val) >= 0 {
+			return fmt.Errorf("%w: node at %d is out of order", ErrBadMappedFile, off)
+		}
+		prev = val
+	}
+	return nil
+}
+
+func (m *MappedSkipList[T]) height(off uint64) int {
+	return int(m.data[off])
+}
+
+// tower returns the offset of the first level of the tower at off, which is
+// the head's for offset 0.
+func (m *MappedSkipList[T]) tower(off uint64) uint64 {
+	if off == 0 {
+		return mappedTowerStart
+	}
+	return off + mappedNodeHeader
+}
+
+func (m *MappedSkipList[T]) next(off uint64, lvl int) uint64 {
+	return binary.LittleEndian.Uint64(m.data[m.tower(off)+uint64(lvl)*mappedLevelSize:])
+}
+
+func (m *MappedSkipList[T]) span(off uint64, lvl int) int {
+	return int(binary.LittleEndian.Uint64(m.data[m.tower(off)+uint64(lvl)*mappedLevelSize+8:]))
+}
+
+func (m *MappedSkipList[T]) key(off uint64) []byte {
+	keyLen := uint64(binary.LittleEndian.Uint32(m.data[off+1:]))
+	keyOff := off + mappedNodeHeader + uint64(m.height(off))*mappedLevelSize
+	return m.data[keyOff : keyOff+keyLen]
+}
+
+func (m *MappedSkipList[T]) value(off uint64) T {
+	return m.decode(m.key(off))
+}
+
+// seek returns the offset of the first node not less than val, or 0, and its
+// rank.
+func (m *MappedSkipList[T]) seek(val T) (uint64, int) {
+	curr, rank := uint64(0), 0
+	for lvl := m.maxLevel; lvl >= 0; lvl-- {
+		for next := m.next(curr, lvl); next != 0 && m.comparator(m.value(next), val) < 0; next = m.next(curr, lvl) {
+			rank += m.span(curr, lvl)
+			curr = next
+		}
+	}
+	return m.next(curr, 0), rank + 1
+}
+
+// Len returns the number of elements in the list.
+func (m *MappedSkipList[T]) Len() int {
+	return m.length
+}
+
+// SearchByValue returns the stored element equal to val.
+//
+// Time Complexity: O(log n) on average
+func (m *MappedSkipList[T]) SearchByValue(val T) (T, bool) {
+	if off, _ := m.seek(val); off != 0 {
+		if found := m.value(off); m.comparator(found, val) == 0 {
+			return found, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Contains reports whether val is in the list.
+func (m *MappedSkipList[T]) Contains(val T) bool {
+	_, found := m.SearchByValue(val)
+	return found
+}
+
+// GetLowerBound returns the smallest element not less than val.
+//
+// Time Complexity: O(log n) on average
+func (m *MappedSkipList[T]) GetLowerBound(val T) (T, bool) {
+	if off, _ := m.seek(val); off != 0 {
+		return m.value(off), true
+	}
+	var zero T
+	return zero, false
+}
+
+// SearchByRank returns the element at the given 1-indexed rank.
+//
+// Time Complexity: O(log n) on average
+func (m *MappedSkipList[T]) SearchByRank(rank int) (T, bool) {
+	var zero T
+	if rank < 1 || rank > m.length {
+		return zero, false
+	}
+
+	curr, pos := uint64(0), 0
+	for lvl := m.maxLevel; lvl >= 0; lvl-- {
+		for pos+m.span(curr, lvl) <= rank {
+			pos += m.span(curr, lvl)
+			curr = m.next(curr, lvl)
+		}
+		if pos == rank {
+			return m.value(curr), true
+		}
+	}
+	return zero, false
+}
+
+// GetRank returns the 1-indexed rank of val.
+func (m *MappedSkipList[T]) GetRank(val T) (int, bool) {
+	off, rank := m.seek(val)
+	if off == 0 || m.comparator(m.value(off), val) != 0 {
+		return -1, false
+	}
+	return rank, true
+}
+
+// Range calls fn for every element in ascending order until fn returns false.
+func (m *MappedSkipList[T]) Range(fn func(val T) bool) {
+	for off := m.next(0, 0); off != 0; off = m.next(off, 0) {
+		if !fn(m.value(off)) {
+			return
+		}
+	}
+}
+
+// NewIterator returns an iterator over the list, positioned before the first
+// element. It is returned by value, so iterating does not allocate.
+func (m *MappedSkipList[T]) NewIterator() MappedIterator[T] {
+	return MappedIterator[T]{m: m}
+}
+
+// Close releases the mapping. The list must not be used afterwards.
+func (m *MappedSkipList[T]) Close() error {
+	data := m.data
+	m.data = nil
+	if data == nil {
+		return nil
+	}
+	return unmapFile(data)
+}
+
+// MappedIterator walks a MappedSkipList in ascending order.
+type MappedIterator[T any] struct {
+	m   *MappedSkipList[T]
+	off uint64
+}
+
+// First positions the iterator at the smallest element.
+func (it *MappedIterator[T]) First() {
+	it.off = it.m.next(0, 0)
+}
+
+// Seek positions the iterator at the first element not less than val.
+func (it *MappedIterator[T]) Seek(val T) {
+	it.off, _ = it.m.seek(val)
+}
+
+// Next advances the iterator.
+func (it *MappedIterator[T]) Next() {
+	it.off = it.m.next(it.off, 0)
+}
+
+// Valid reports whether the iterator is positioned at an element.
+func (it *MappedIterator[T]) Valid() bool {
+	return it.off != 0
+}
+
+// Value returns the element at the iterator's position.
+func (it *MappedIterator[T]) Value() T {
+	return it.m.value(it.off)
+}
+
+// mappedDecoderFor returns a decoder for the built-in encoding of T that
+// writes straight into a T without reflection, so decoding does not allocate.
+// Strings are not copied: they point into the mapped data.
+func mappedDecoderFor[T cmp.Ordered]() func([]byte) T {
+	t := reflect.TypeFor[T]()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		varint := func(data []byte) int64 {
+			x, _ := binary.Varint(data)
+			return x
+		}
+		switch t.Size() {
+		case 1:
+			return decodeAs[T](func(data []byte) int8 { return int8(varint(data)) })
+		case 2:
+			return decodeAs[T](func(data []byte) int16 { return int16(varint(data)) })
+		case 4:
+			return decodeAs[T](func(data []byte) int32 { return int32(varint(data)) })
+		}
+		return decodeAs[T](varint)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		uvarint := func(data []byte) uint64 {
+			x, _ := binary.Uvarint(data)
+			return x
+		}
+		switch t.Size() {
+		case 1:
+			return decodeAs[T](func(data []byte) uint8 { return uint8(uvarint(data)) })
+		case 2:
+			return decodeAs[T](func(data []byte) uint16 { return uint16(uvarint(data)) })
+		case 4:
+			return decodeAs[T](func(data []byte) uint32 { return uint32(uvarint(data)) })
+		}
+		return decodeAs[T](uvarint)
+	case reflect.Float32:
+		return decodeAs[T](func(data []byte) float32 {
+			return float32(mappedFloat(data))
+		})
+	case reflect.Float64:
+		return decodeAs[T](mappedFloat)
+	default:
+		return decodeAs[T](func(data []byte) string {
+			return unsafe.String(unsafe.SliceData(data), len(data))
+		})
+	}
+}
+
+// mappedFloat decodes a built-in float encoding. Opening does not decode
+// elements, so a key of the wrong length reads as 0 rather than panicking.
+func mappedFloat(data []byte) float64 {
+	if len(data) != 8 {
+		return 0
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(data))
+}
+
+// decodeAs adapts a decoder for U to T, whose underlying type must be U.
+func decodeAs[T, U any](decode func([]byte) U) func([]byte) T {
+	return func(data []byte) T {
+		var val T
+		*(*U)(unsafe.Pointer(&val)) = decode(data)
+		return val
+	}
+}
@@ -0,0 +1,240 @@
+package skiplist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Mapped helpers
+// ------------------------------------------------------------
+
+func writeMappedFile[T any](t *testing.T, sl *SkipList[T]) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := sl.WriteMapped(&buf); err != nil {
+		t.Fatalf("WriteMapped returned %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "list.map")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile returned %v", err)
+	}
+	return path
+}
+
+// ------------------------------------------------------------
+// Mapped tests
+// ------------------------------------------------------------
+
+func TestMapped_MatchesSourceList(t *testing.T) {
+	rng := rand.New(rand.NewSource(47))
+	for _, size := range []int{0, 1, 17, 2000} {
+		sl := NewSkipList[int]()
+		for sl.Len() < size {
+			sl.Add(rng.Intn(10 * (size + 1)))
+		}
+		m, err := OpenMapped[int](writeMappedFile(t, sl))
+		if err != nil {
+			t.Fatalf("size %d: OpenMapped returned %v", size, err)
+		}
+
+		if m.Len() != sl.Len() || m.maxLevel != sl.maxLevel {
+			t.Fatalf("size %d: mapped list has len %d and max level %d", size, m.Len(), m.maxLevel)
+		}
+		got, want := []int{}, []int{}
+		m.Range(func(val int) bool {
+			got = append(got, val)
+			return true
+		})
+		sl.Range(func(val int) bool {
+			want = append(want, val)
+			return true
+		})
+		if !slicesEqual(got, want) {
+			t.Fatalf("size %d: mapped values differ from the list", size)
+		}
+
+		for probe := -1; probe <= 10*(size+1); probe++ {
+			val, found := m.SearchByValue(probe)
+			if found != sl.Contains(probe) || (found && val != probe) {
+				t.Fatalf("size %d: SearchByValue(%d) = %d %v", size, probe, val, found)
+			}
+			lb, ok := m.GetLowerBound(probe)
+			want, wantOK := sl.GetLowerBound(probe)
+			if ok != wantOK || (ok && lb != want.Value()) {
+				t.Fatalf("size %d: GetLowerBound(%d) = %d %v", size, probe, lb, ok)
+			}
+			rank, ok := m.GetRank(probe)
+			wantRank, wantOK := sl.GetRank(probe)
+			if ok != wantOK || rank != wantRank {
+				t.Fatalf("size %d: GetRank(%d) = %d, want %d", size, probe, rank, wantRank)
+			}
+		}
+		for rank := 0; rank <= size+1; rank++ {
+			val, ok := m.SearchByRank(rank)
+			want, wantOK := sl.SearchByRank(rank)
+			if ok != wantOK || (ok && val != want.Value()) {
+				t.Fatalf("size %d: SearchByRank(%d) = %d %v", size, rank, val, ok)
+			}
+		}
+		if err := m.Close(); err != nil {
+			t.Fatalf("Close returned %v", err)
+		}
+	}
+}
+
+func TestMapped_Iterator(t *testing.T) {
+	sl := NewSkipList[string]()
+	for _, s := range []string{"kiwi", "apple", "fig", "banana", "cherry"} {
+		sl.Add(s)
+	}
+	m, err := OpenMapped[string](writeMappedFile(t, sl))
+	if err != nil {
+		t.Fatalf("OpenMapped returned %v", err)
+	}
+	defer m.Close()
+
+	var got []string
+	it := m.NewIterator()
+	for it.Seek("c"); it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if !stringsEqual(got, []string{"cherry", "fig", "kiwi"}) {
+		t.Fatalf("unexpected iteration from c: %v", got)
+	}
+	it.First()
+	if !it.Valid() || it.Value() != "apple" {
+		t.Fatal("First should position at apple")
+	}
+}
+
+func TestMapped_LookupsDoNotAllocate(t *testing.T) {
+	ints := NewSkipList[int64]()
+	strs := NewSkipList[string]()
+	for i := 0; i < 1000; i++ {
+		ints.Add(int64(i * 3))
+		strs.Add(fmt.Sprintf("key-%04d", i))
+	}
+	mi, err := OpenMapped[int64](writeMappedFile(t, ints))
+	if err != nil {
+		t.Fatalf("OpenMapped returned %v", err)
+	}
+	defer mi.Close()
+	ms, err := OpenMapped[string](writeMappedFile(t, strs))
+	if err != nil {
+		t.Fatalf("OpenMapped returned %v", err)
+	}
+	defer ms.Close()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		mi.SearchByValue(1500)
+		mi.GetLowerBound(1501)
+		mi.SearchByRank(700)
+		ms.SearchByValue("key-0500")
+		ms.GetRank("key-0999")
+		it := ms.NewIterator()
+		for it.Seek("key-0990"); it.Valid(); it.Next() {
+			_ = it.Value()
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations per lookup, got %v", allocs)
+	}
+}
+
+func TestMapped_ComparableWithCodec(t *testing.T) {
+	sl := NewComparableSkipList[account]()
+	sl.UseCodec(accountCodec{})
+	sl.Add(account{"carol", 30})
+	sl.Add(account{"alice", 10})
+	sl.Add(account{"bob", 20})
+
+	path := writeMappedFile(t, sl)
+	if _, err := OpenComparableMapped[account](path, nil); !errors.Is(err, ErrNoCodec) {
+		t.Fatalf("expected ErrNoCodec without a codec, got %v", err)
+	}
+	m, err := OpenComparableMapped[account](path, accountCodec{})
+	if err != nil {
+		t.Fatalf("OpenComparableMapped returned %v", err)
+	}
+	defer m.Close()
+	if got, ok := m.SearchByValue(account{id: "bob"}); !ok || got.balance != 20 {
+		t.Fatalf("SearchByValue(bob) = %v %v", got, ok)
+	}
+	if rank, _ := m.GetRank(account{id: "carol"}); rank != 3 {
+		t.Fatalf("expected carol at rank 3, got %d", rank)
+	}
+}
+
+func TestOpenMapped_RejectsCorruptFiles(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 0; i < 50; i++ {
+		sl.Add(i)
+	}
+	var buf bytes.Buffer
+	sl.WriteMapped(&buf)
+	good := buf.Bytes()
+
+	corrupt := map[string][]byte{
+		"truncated": good[:len(good)-10],
+		"bit flip":  append(append([]byte(nil), good[:300]...), append([]byte{good[300] ^ 1}, good[301:]...)...),
+		"bad span": func() []byte {
+			data := bytes.Clone(good)
+			data[mappedTowerStart+8]++ // the head's level-0 span
+			return resealSnapshot(data)
+		}(),
+		"bad link": func() []byte {
+			data := bytes.Clone(good)
+			data[mappedTowerStart]++ // the head's level-0 next offset
+			return resealSnapshot(data)
+		}(),
+	}
+	for name, data := range corrupt {
+		path := filepath.Join(t.TempDir(), "bad.map")
+		os.WriteFile(path, data, 0o644)
+		if _, err := OpenMapped[int](path); !errors.Is(err, ErrBadMappedFile) {
+			t.Fatalf("%s: expected ErrBadMappedFile, got %v", name, err)
+		}
+	}
+}
+
+func TestMapped_ValidateChecksElementOrder(t *testing.T) {
+	sl := NewSkipList[int]()
+	for i := 0; i < 50; i++ {
+		sl.Add(i)
+	}
+	path := writeMappedFile(t, sl)
+	m, err := OpenMapped[int](path)
+	if err != nil {
+		t.Fatalf("OpenMapped returned %v", err)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate returned %v for a good file", err)
+	}
+	keyOffset := func(off uint64) int {
+		return int(off) + mappedNodeHeader + m.height(off)*mappedLevelSize
+	}
+	first := m.next(0, 0)
+	i, j := keyOffset(first), keyOffset(m.next(first, 0))
+	m.Close()
+
+	// swap the first two single-byte keys: the structure stays valid, so the
+	// file still opens, but the elements are out of order
+	data, _ := os.ReadFile(path)
+	data[i], data[j] = data[j], data[i]
+	os.WriteFile(path, resealSnapshot(data), 0o644)
+
+	m, err = OpenMapped[int](path)
+	if err != nil {
+		t.Fatalf("OpenMapped returned %v; it does not check element order", err)
+	}
+	defer m.Close()
+	if err := m.Validate(); !errors.Is(err, ErrBadMappedFile) {
+		t.Fatalf("expected ErrBadMappedFile, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package skiplist
+
+import (
+	"cmp"
+	"sync"
+	"sync/atomic"
+)
+
+// Iterator walks key/value pairs in ascending key order. A new iterator is
+// not positioned; call First or Seek before reading from it.
+type Iterator[K, V any] interface {
+	// First positions the iterator at the smallest key.
+	First()
+	// Seek positions the iterator at the first key greater than or equal to key.
+	Seek(key K)
+	// Next moves the iterator to the next key.
+	Next()
+	// Valid reports whether the iterator is positioned at a key.
+	Valid() bool
+	// Key returns the current key. It must only be called while Valid.
+	Key() K
+	// Value returns the current value. It must only be called while Valid.
+	Value() V
+}
+
+// memEntry is one version of a key. Entries are ordered by key ascending and
+// then by sequence number descending, so the newest version of a key comes
+// first and a seek to (key, seq) lands on the newest version visible at seq.
+type memEntry[K, V any] struct {
+	key       K
+	seq       uint64
+	tombstone bool
+	value     V
+}
+
+// Memtable is a multi-version ordered map in the style of the LevelDB and
+// Badger memtables. Every write is stamped with a new sequence number and
+// appended as a new version instead of replacing the old one; deletes append
+// a tombstone. Reads at a sequence number see exactly the writes made up to
+// it, which gives consistent snapshots without copying.
+//
+// A Memtable is safe for concurrent use. Versions live in a lock-free
+// ConcurrentSkipList and are never removed, so reads and iterators take no
+// locks and never wait for a writer. Writes are serialized, so there is only
+// ever a single writer; each version is published by advancing the last
+// sequence number only once it is linked.
+type Memtable[K, V any] struct {
+	mu     sync.Mutex // serializes writers
+	list   *ConcurrentSkipList[memEntry[K, V]]
+	keyCmp Comparator[K]
+	seq    atomic.Uint64
+}
+
+// NewMemtable creates a memtable for ordered key types.
+func NewMemtable[K cmp.Ordered, V any]() *Memtable[K, V] {
+	return NewMemtableFunc[K, V](cmp.Compare[K])
+}
+
+// NewMemtableFunc creates a memtable whose keys are ordered by keyCmp.
+func NewMemtableFunc[K, V any](keyCmp Comparator[K]) *Memtable[K, V] {
+	return &Memtable[K, V]{
+		list: newConcurrentSkipList(func(a, b memEntry[K, V]) int {
+			if c := keyCmp(a.key, b.key); c != 0 {
+				return c
+			}
+			return cmp.Compare(b.seq, a.seq)
+		}),
+		keyCmp: keyCmp,
+	}
+}
+
+// Put writes a new version of key and returns its sequence number.
+//
+// Time Complexity: O(log n) average, where n is the number of versions
+func (m *Memtable[K, V]) Put(key K, value V) uint64 {
+	return m.append(memEntry[K, V]{key: key, value: value})
+}
+
+// Delete writes a tombstone for key and returns its sequence number. Reads at
+// or after that sequence number no longer see the key.
+func (m *Memtable[K, V]) Delete(key K) uint64 {
+	return m.append(memEntry[K, V]{key: key, tombstone: true})
+}
+
+func (m *Memtable[K, V]) append(e memEntry[K, V]) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// readers take their snapshot from seq, so the version must be linked
+	// before seq covers it
+	e.seq = m.seq.Load() + 1
+	m.list.Add(e)
+	m.seq.Store(e.seq)
+	return e.seq
+}
+
+// LastSeq returns the sequence number of the latest write, or 0 if nothing has
+// been written. Passing it to GetAt or NewIterator reads a snapshot of the
+// memtable as of now.
+func (m *Memtable[K, V]) LastSeq() uint64 {
+	return m.seq.Load()
+}
+
+// Get returns the latest value of key.
+func (m *Memtable[K, V]) Get(key K) (V, bool) {
+	return m.GetAt(key, m.seq.Load())
+}
+
+// GetAt returns the value of key as of sequence number seq: the newest version
+// written at or before seq, unless that version is a tombstone.
+//
+// Time Complexity: O(log n) average, where n is the number of versions
+func (m *Memtable[K, V]) GetAt(key K, seq uint64) (V, bool) {
+	var zero V
+	e, found := m.list.GetLowerBound(memEntry[K, V]{key: key, seq: seq})
+	if !found || e.tombstone || m.keyCmp(e.key, key) != 0 {
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Len returns the number of versions in the memtable, tombstones included. It
+// is exact when no write is in progress.
+func (m *Memtable[K, V]) Len() int {
+	return m.list.Len()
+}
+
+// NewIterator returns an iterator over the memtable as of sequence number seq.
+// It yields the newest version of each key written at or before seq and skips
+// keys whose newest such version is a tombstone. Writes made after seq are
+// never visible to it, even if they happen while it is in use.
+func (m *Memtable[K, V]) NewIterator(seq uint64) *MemtableIterator[K, V] {
+	return &MemtableIterator[K, V]{m: m, seq: seq}
+}
+
+// MemtableIterator is a snapshot iterator over a Memtable. It may be used
+// while other goroutines write to the memtable, but is itself not safe for
+// concurrent use.
+//
+// Memtables never remove nodes, so the iterator keeps its node between calls
+// and each step only loads the node's level-0 link, without locking. Versions
+// linked after the snapshot are skipped by their sequence number.
+type MemtableIterator[K, V any] struct {
+	m    *Memtable[K, V]
+	seq  uint64
+	node *concurrentNode[memEntry[K, V]]
+}
+
+var _ Iterator[int, int] = (*MemtableIterator[int, int])(nil)
+
+// First positions the iterator at the smallest visible key.
+func (it *MemtableIterator[K, V]) First() {
+	it.settle(it.m.list.head.next[0].Load().node)
+}
+
+// Seek positions the iterator at the first visible key greater than or equal
+// to key.
+func (it *MemtableIterator[K, V]) Seek(key K) {
+	it.settle(it.m.list.ceiling(memEntry[K, V]{key: key, seq: it.seq}))
+}
+
+// Next moves the iterator to the next visible key. It is a no-op if the
+// iterator is not Valid.
+func (it *MemtableIterator[K, V]) Next() {
+	if it.node == nil {
+		return
+	}
+	it.settle(it.skipKey(it.node))
+}
+
+// Valid reports whether the iterator is positioned at a key.
+func (it *MemtableIterator[K, V]) Valid() bool {
+	return it.node != nil
+}
+
+// Key returns the current key.
+func (it *MemtableIterator[K, V]) Key() K {
+	return it.node.val.key
+}
+
+// Value returns the value of the current key as of the iterator's snapshot.
+func (it *MemtableIterator[K, V]) Value() V {
+	return it.node.val.value
+}
+
+// Seq returns the sequence number of the version the iterator is positioned at.
+func (it *MemtableIterator[K, V]) Seq() uint64 {
+	return it.node.val.seq
+}
+
+// settle moves forward from node to the first entry that is the newest version
+// of its key visible at the snapshot and not a tombstone.
+func (it *MemtableIterator[K, V]) settle(node *concurrentNode[memEntry[K, V]]) {
+	for node != nil {
+		switch {
+		case node.val.seq > it.seq:
+			node = node.next[0].Load().node
+		case node.val.tombstone:
+			node = it.skipKey(node)
+		default:
+			it.node = node
+			return
+		}
+	}
+	it.node = nil
+}
+
+// skipKey returns the first entry after node with a different key.
+func (it *MemtableIterator[K, V]) skipKey(node *concurrentNode[memEntry[K, V]]) *concurrentNode[memEntry[K, V]] {
+	key := node.val.key
+	node = node.next[0].Load().node
+	for node != nil && it.m.keyCmp(node.val.key, key) == 0 {
+		node = node.next[0].Load().node
+	}
+	return node
+}
@@ -0,0 +1,167 @@
+package skiplist
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ------------------------------------------------------------
+// Memtable helpers
+// ------------------------------------------------------------
+
+// collectIter drains it from First and returns "key=value" pairs.
+func collectIter(it Iterator[string, int]) []string {
+	var out []string
+	for it.First(); it.Valid(); it.Next() {
+		out = append(out, fmt.Sprintf("%s=%d", it.Key(), it.Value()))
+	}
+	return out
+}
+
+// ------------------------------------------------------------
+// Memtable tests
+// ------------------------------------------------------------
+
+func TestMemtable_GetAt(t *testing.T) {
+	m := NewMemtable[string, int]()
+
+	s1 := m.Put("a", 1)
+	s2 := m.Put("a", 2)
+	s3 := m.Delete("a")
+	s4 := m.Put("a", 4)
+
+	if s1 != 1 || s4 != 4 || m.LastSeq() != 4 {
+		t.Fatalf("sequence numbers should count writes, got %d..%d (last %d)", s1, s4, m.LastSeq())
+	}
+	if m.Len() != 4 {
+		t.Fatalf("every write should append a version, Len=%d", m.Len())
+	}
+
+	cases := []struct {
+		seq   uint64
+		want  int
+		found bool
+	}{
+		{0, 0, false},
+		{s1, 1, true},
+		{s2, 2, true},
+		{s3, 0, false},
+		{s4, 4, true},
+		{100, 4, true},
+	}
+	for _, c := range cases {
+		if v, ok := m.GetAt("a", c.seq); ok != c.found || v != c.want {
+			t.Fatalf("GetAt(a, %d): got %d (%v), want %d (%v)", c.seq, v, ok, c.want, c.found)
+		}
+	}
+
+	if _, ok := m.Get("b"); ok {
+		t.Fatal("missing key should not be found")
+	}
+	if v, ok := m.Get("a"); !ok || v != 4 {
+		t.Fatalf("Get should return the latest version, got %d (%v)", v, ok)
+	}
+}
+
+func TestMemtable_SnapshotIterator(t *testing.T) {
+	m := NewMemtable[string, int]()
+	m.Put("b", 1)
+	m.Put("a", 1)
+	m.Put("c", 1)
+	snap := m.LastSeq()
+
+	m.Put("b", 2)
+	m.Delete("c")
+	m.Put("d", 1)
+
+	if got := collectIter(m.NewIterator(snap)); !stringsEqual(got, []string{"a=1", "b=1", "c=1"}) {
+		t.Fatalf("snapshot should not see later writes, got %v", got)
+	}
+	if got := collectIter(m.NewIterator(m.LastSeq())); !stringsEqual(got, []string{"a=1", "b=2", "d=1"}) {
+		t.Fatalf("latest view should hide deleted keys, got %v", got)
+	}
+
+	it := m.NewIterator(m.LastSeq())
+	if it.Seek("bb"); !it.Valid() || it.Key() != "d" {
+		t.Fatal("Seek should skip the tombstoned key c")
+	}
+	if it.Seek("b"); !it.Valid() || it.Value() != 2 || it.Seq() != 4 {
+		t.Fatalf("Seek(b) should land on the newest version, got %d@%d", it.Value(), it.Seq())
+	}
+	if it.Seek("e"); it.Valid() {
+		t.Fatal("Seek past the last key should invalidate the iterator")
+	}
+	it.Next() // no-op when not Valid
+}
+
+func TestMemtable_SnapshotIsStableUnderWrites(t *testing.T) {
+	m := NewMemtable[string, int]()
+	for i := 0; i < 100; i++ {
+		m.Put(fmt.Sprintf("k%03d", i), 0)
+	}
+	snap := m.LastSeq()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for round := 1; round <= 20; round++ {
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("k%03d", i)
+				if i%3 == 0 {
+					m.Delete(key)
+				} else {
+					m.Put(key, round)
+				}
+			}
+		}
+	}()
+
+	for r := 0; r < 20; r++ {
+		it := m.NewIterator(snap)
+		n := 0
+		for it.First(); it.Valid(); it.Next() {
+			if it.Value() != 0 {
+				t.Errorf("snapshot saw a later write to %s", it.Key())
+			}
+			n++
+		}
+		if n != 100 {
+			t.Errorf("snapshot should see 100 keys, saw %d", n)
+		}
+	}
+	wg.Wait()
+}
+
+func TestMemtable_ReadersDoNotWaitForWriters(t *testing.T) {
+	m := NewMemtable[string, int]()
+	m.Put("a", 1)
+	m.Put("b", 2)
+
+	// hold the writer lock as a write in progress would
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if v, ok := m.Get("a"); !ok || v != 1 {
+			t.Errorf("Get(a) = %d, %v", v, ok)
+		}
+		n := 0
+		it := m.NewIterator(m.LastSeq())
+		for it.Seek("a"); it.Valid(); it.Next() {
+			n++
+		}
+		if n != 2 {
+			t.Errorf("iterator saw %d keys, want 2", n)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reads blocked behind a writer")
+	}
+}
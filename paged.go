@@ -0,0 +1,650 @@
+package skiplist
+
+import (
+	"cmp"
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// Errors returned by PagedSkipList.
+var (
+	// ErrBadPageFile is returned by OpenPaged when the file is not a paged skip
+	// list or its header fails its checksum.
+	ErrBadPageFile = errors.New("skiplist: bad page file")
+	// ErrValueTooLarge is returned when an encoded value does not fit in a
+	// page next to a full-height tower.
+	ErrValueTooLarge = errors.New("skiplist: value too large for page")
+)
+
+// DefaultPageSize and MinPageSize bound the page size of a paged skip list. A
+// page must hold the header, which includes the head's full tower.
+const (
+	DefaultPageSize   = 512
+	MinPageSize       = pageHeaderSize
+	DefaultCachePages = 1024
+)
+
+// PagedOptions configures a PagedSkipList.
+type PagedOptions[T any] struct {
+	// PageSize is the size of every page of a new file. It is ignored when
+	// opening an existing file, whose page size is recorded in its header. The
+	// default is DefaultPageSize.
+	PageSize int
+	// CachePages is the number of pages the buffer pool keeps in memory. The
+	// default is DefaultCachePages.
+	CachePages int
+	// SyncOnCommit makes every Add and Delete write its dirty pages and fsync
+	// the file before returning.
+	SyncOnCommit bool
+	// Codec encodes values into pages. It is required for element types
+	// without a built-in codec.
+	Codec Codec[T]
+}
+
+// Page 0 holds the header, laid out as below with little-endian integers.
+// Every other page holds one node, or a link in the free list.
+//
+//	header  magic "SKPG", version, page size (4), maxLevel (1), length (8),
+//	        page count (8), first free page (8), levelCount (8 each), the head's
+//	        tower as (next page, span) pairs (8 + 8 each), CRC-32C (4)
+//	node    height (1, never 0), value length (2), the tower as (next page,
+//	        span) pairs, the encoded value
+//	free    0 (1), next free page (8)
+//
+// Page number 0 doubles as the nil pointer, since no node lives there.
+const (
+	pageMagic       = "SKPG"
+	pageVersion     = 1
+	pageLevelSize   = 16
+	pageTowerSize   = (MaxLevelCap + 1) * pageLevelSize
+	pageHeaderSize  = 4 + 1 + 4 + 1 + 3*8 + (MaxLevelCap+1)*8 + pageTowerSize + 4
+	pageNodeMinimum = 1 + 2
+)
+
+// pagedLevel is one level of a tower on disk.
+type pagedLevel struct {
+	next uint64
+	span int
+}
+
+// pagedNode is a decoded copy of a node page. Changes are made to the copy and
+// written back, so a node is never modified in a page that may be evicted.
+type pagedNode[T any] struct {
+	page   uint64
+	val    T
+	levels []pagedLevel
+}
+
+// PagedSkipList is a skip list stored in a file, for indexes that don't fit in
+// memory. Every node lives in its own fixed-size page and links to other nodes
+// by page number. Nodes keep the same rank spans as SkipList, so SearchByRank
+// and GetRank work without reading the whole file.
+//
+// Pages are read through a buffer pool with LRU eviction, and dirty pages are
+// written back when they are evicted or on Commit. A crash while pages are
+// being written back can leave the file inconsistent; there is no journal.
+//
+// A PagedSkipList is not safe for concurrent use.
+type PagedSkipList[T any] struct {
+	f          *os.File
+	comparator Comparator[T]
+	codec      Codec[T]
+	pool       *bufferPool
+	sync       bool
+	pageSize   int
+
+	// the header, kept in memory and written back on Commit
+	maxLevel   int
+	length     int
+	pages      uint64
+	free       uint64
+	levelCount [MaxLevelCap + 1]int
+	head       [MaxLevelCap + 1]pagedLevel
+	dirty      bool
+}
+
+// OpenPaged opens the paged skip list stored in f for ordered types, or
+// initializes one if f is empty. The caller keeps ownership of f and closes it
+// after calling Close.
+func OpenPaged[T cmp.Ordered](f *os.File, opts PagedOptions[T]) (*PagedSkipList[T], error) {
+	return openPaged(f, cmp.Compare[T], opts)
+}
+
+// OpenComparablePaged opens a paged skip list for types that implement the
+// Comparable interface.
+func OpenComparablePaged[T Comparable[T]](f *os.File, opts PagedOptions[T]) (*PagedSkipList[T], error) {
+	return openPaged(f, func(a, b T) int { return a.Compare(b) }, opts)
+}
+
+func openPaged[T any](f *os.File, comparator Comparator[T], opts PagedOptions[T]) (*PagedSkipList[T], error) {
+	codec, err := codecOrBuiltIn(opts.Codec)
+	if err != nil {
+		return nil, err
+	}
+	if opts.PageSize == 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	if opts.CachePages <= 0 {
+		opts.CachePages = DefaultCachePages
+	}
+
+	p := &PagedSkipList[T]{f: f, comparator: comparator, codec: codec, sync: opts.SyncOnCommit}
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		if opts.PageSize < MinPageSize {
+			return nil, fmt.Errorf("skiplist: page size %d is below the minimum %d", opts.PageSize, MinPageSize)
+		}
+		p.pageSize = opts.PageSize
+		p.pages = 1
+		for i := range p.head {
+			p.head[i].span = 1
+		}
+		p.dirty = true
+	} else if err := p.readHeader(); err != nil {
+		return nil, err
+	}
+
+	p.pool = newBufferPool(f, p.pageSize, max(opts.CachePages, 2*(MaxLevelCap+1)))
+	if p.dirty {
+		if err := p.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *PagedSkipList[T]) readHeader() error {
+	buf := make([]byte, pageHeaderSize)
+	if _, err := p.f.ReadAt(buf, 0); err != nil {
+		return fmt.Errorf("%w: %v", ErrBadPageFile, err)
+	}
+	if string(buf[:4]) != pageMagic || buf[4] != pageVersion {
+		return fmt.Errorf("%w: missing header", ErrBadPageFile)
+	}
+	body := buf[:pageHeaderSize-4]
+	if crc32.Checksum(body, snapshotTable) != binary.LittleEndian.Uint32(buf[pageHeaderSize-4:]) {
+		return fmt.Errorf("%w: header checksum mismatch", ErrBadPageFile)
+	}
+
+	p.pageSize = int(binary.LittleEndian.Uint32(buf[5:]))
+	p.maxLevel = int(buf[9])
+	p.length = int(binary.LittleEndian.Uint64(buf[10:]))
+	p.pages = binary.LittleEndian.Uint64(buf[18:])
+	p.free = binary.LittleEndian.Uint64(buf[26:])
+	off := 34
+	for i := range p.levelCount {
+		p.levelCount[i] = int(binary.LittleEndian.Uint64(buf[off:]))
+		off += 8
+	}
+	decodeTower(buf[off:], p.head[:])
+	if p.pageSize < MinPageSize || p.maxLevel > MaxLevelCap {
+		return fmt.Errorf("%w: corrupt header", ErrBadPageFile)
+	}
+	return nil
+}
+
+func (p *PagedSkipList[T]) encodeHeader() []byte {
+	buf := make([]byte, 0, p.pageSize)
+	buf = append(buf, pageMagic...)
+	buf = append(buf, pageVersion)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(p.pageSize))
+	buf = append(buf, byte(p.maxLevel))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(p.length))
+	buf = binary.LittleEndian.AppendUint64(buf, p.pages)
+	buf = binary.LittleEndian.AppendUint64(buf, p.free)
+	for _, n := range p.levelCount {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(n))
+	}
+	buf = appendTower(buf, p.head[:])
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(buf, snapshotTable))
+	return buf[:p.pageSize]
+}
+
+func appendTower(buf []byte, levels []pagedLevel) []byte {
+	for _, l := range levels {
+		buf = binary.LittleEndian.AppendUint64(buf, l.next)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(l.span))
+	}
+	return buf
+}
+
+func decodeTower(buf []byte, levels []pagedLevel) {
+	for i := range levels {
+		levels[i].next = binary.LittleEndian.Uint64(buf[i*pageLevelSize:])
+		levels[i].span = int(binary.LittleEndian.Uint64(buf[i*pageLevelSize+8:]))
+	}
+}
+
+// readNode returns a copy of the node at page. Page 0 is the head.
+func (p *PagedSkipList[T]) readNode(page uint64) (pagedNode[T], error) {
+	if page == 0 {
+		return pagedNode[T]{levels: append([]pagedLevel(nil), p.head[:]...)}, nil
+	}
+	data, err := p.pool.get(page)
+	if err != nil {
+		return pagedNode[T]{}, err
+	}
+
+	height := int(data[0])
+	valLen := int(binary.LittleEndian.Uint16(data[1:]))
+	valOff := pageNodeMinimum + height*pageLevelSize
+	if height == 0 || height > MaxLevelCap+1 || valOff+valLen > len(data) {
+		return pagedNode[T]{}, fmt.Errorf("%w: corrupt node page %d", ErrBadPageFile, page)
+	}
+	val, err := p.codec.Decode(data[valOff : valOff+valLen])
+	if err != nil {
+		return pagedNode[T]{}, fmt.Errorf("%w: node page %d: %v", ErrBadPageFile, page, err)
+	}
+
+	node := pagedNode[T]{page: page, val: val, levels: make([]pagedLevel, height)}
+	decodeTower(data[pageNodeMinimum:], node.levels)
+	return node, nil
+}
+
+// writeLevel updates one level of the node at page.
+func (p *PagedSkipList[T]) writeLevel(page uint64, lvl int, l pagedLevel) error {
+	if page == 0 {
+		p.head[lvl] = l
+		p.dirty = true
+		return nil
+	}
+	data, err := p.pool.getForWrite(page)
+	if err != nil {
+		return err
+	}
+	appendTower(data[pageNodeMinimum+lvl*pageLevelSize:][:0], []pagedLevel{l})
+	return nil
+}
+
+// addSpan adds delta to the span of one level of the node at page.
+func (p *PagedSkipList[T]) addSpan(page uint64, lvl, delta int) error {
+	node, err := p.readNode(page)
+	if err != nil {
+		return err
+	}
+	l := node.levels[lvl]
+	l.span += delta
+	return p.writeLevel(page, lvl, l)
+}
+
+// allocNode writes a new node into a free or new page and returns the page.
+func (p *PagedSkipList[T]) allocNode(encoded []byte, levels []pagedLevel) (uint64, error) {
+	page := p.free
+	if page != 0 {
+		data, err := p.pool.get(page)
+		if err != nil {
+			return 0, err
+		}
+		p.free = binary.LittleEndian.Uint64(data[1:])
+	} else {
+		page = p.pages
+		p.pages++
+	}
+	p.dirty = true
+
+	data := p.pool.create(page)
+	data[0] = byte(len(levels))
+	binary.LittleEndian.PutUint16(data[1:], uint16(len(encoded)))
+	appendTower(data[pageNodeMinimum:pageNodeMinimum], levels)
+	copy(data[pageNodeMinimum+len(levels)*pageLevelSize:], encoded)
+	return page, nil
+}
+
+// freeNode pushes page onto the free list.
+func (p *PagedSkipList[T]) freeNode(page uint64) {
+	data := p.pool.create(page)
+	binary.LittleEndian.PutUint64(data[1:], p.free)
+	p.free = page
+	p.dirty = true
+}
+
+// descend finds, for every level, the page of the last node before val and
+// its rank, like finger search in SkipList. It also returns the first node at
+// or after val on level 0, if there is one.
+func (p *PagedSkipList[T]) descend(val T) (hierarchy [MaxLevelCap + 1]uint64, rank [MaxLevelCap + 1]int, next *pagedNode[T], err error) {
+	curr, err := p.readNode(0)
+	if err != nil {
+		return hierarchy, rank, nil, err
+	}
+	pos := 0
+
+	for lvl := p.maxLevel; lvl >= 0; lvl-- {
+		for curr.levels[lvl].next != 0 {
+			n, err := p.readNode(curr.levels[lvl].next)
+			if err != nil {
+				return hierarchy, rank, nil, err
+			}
+			if p.comparator(n.val, val) >= 0 {
+				if lvl == 0 {
+					next = &n
+				}
+				break
+			}
+			pos += curr.levels[lvl].span
+			curr = n
+		}
+		hierarchy[lvl], rank[lvl] = curr.page, pos
+	}
+	return hierarchy, rank, next, nil
+}
+
+// Add inserts val if it is not already present.
+//
+// Time Complexity: O(log n) page reads on average
+func (p *PagedSkipList[T]) Add(val T) error {
+	encoded, err := p.codec.Encode(val)
+	if err != nil {
+		return err
+	}
+	if pageNodeMinimum+pageTowerSize+len(encoded) > p.pageSize || len(encoded) > 0xffff {
+		return fmt.Errorf("%w: %d bytes", ErrValueTooLarge, len(encoded))
+	}
+
+	hierarchy, rank, next, err := p.descend(val)
+	if err != nil {
+		return err
+	}
+	if next != nil && p.comparator(next.val, val) == 0 {
+		return nil
+	}
+
+	lvl := randomLevel()
+	if lvl > p.maxLevel {
+		for i := p.maxLevel + 1; i <= lvl; i++ {
+			hierarchy[i], rank[i] = 0, 0
+		}
+		p.maxLevel = lvl
+	}
+
+	// the new node takes over part of each predecessor's span, as in link
+	skipped := rank[0]
+	levels := make([]pagedLevel, lvl+1)
+	for i := 0; i <= lvl; i++ {
+		pred, err := p.readNode(hierarchy[i])
+		if err != nil {
+			return err
+		}
+		levels[i] = pagedLevel{next: pred.levels[i].next, span: rank[i] + pred.levels[i].span - skipped}
+	}
+	page, err := p.allocNode(encoded, levels)
+	if err != nil {
+		return err
+	}
+	for i := 0; i <= lvl; i++ {
+		if err := p.writeLevel(hierarchy[i], i, pagedLevel{next: page, span: skipped - rank[i] + 1}); err != nil {
+			return err
+		}
+		p.levelCount[i]++
+	}
+
+	for i := lvl + 1; i <= p.maxLevel; i++ {
+		if err := p.addSpan(hierarchy[i], i, 1); err != nil {
+			return err
+		}
+	}
+	for i := p.maxLevel + 1; i <= MaxLevelCap; i++ {
+		p.head[i].span++
+	}
+	p.length++
+	p.dirty = true
+	return p.commitIfSync()
+}
+
+// Delete removes val if it exists.
+//
+// Time Complexity: O(log n) page reads on average
+func (p *PagedSkipList[T]) Delete(val T) error {
+	hierarchy, _, next, err := p.descend(val)
+	if err != nil {
+		return err
+	}
+	if next == nil || p.comparator(next.val, val) != 0 {
+		return nil
+	}
+
+	height := len(next.levels)
+	for i := 0; i < height; i++ {
+		pred, err := p.readNode(hierarchy[i])
+		if err != nil {
+			return err
+		}
+		l := pagedLevel{next: next.levels[i].next, span: pred.levels[i].span + next.levels[i].span - 1}
+		if err := p.writeLevel(hierarchy[i], i, l); err != nil {
+			return err
+		}
+		p.levelCount[i]--
+	}
+	lvl := height
+	for ; lvl <= p.maxLevel; lvl++ {
+		if err := p.addSpan(hierarchy[lvl], lvl, -1); err != nil {
+			return err
+		}
+	}
+	for ; lvl <= MaxLevelCap; lvl++ {
+		p.head[lvl].span--
+	}
+	for p.maxLevel > 0 && p.levelCount[p.maxLevel] == 0 {
+		p.maxLevel--
+	}
+
+	p.freeNode(next.page)
+	p.length--
+	p.dirty = true
+	return p.commitIfSync()
+}
+
+// Search returns the stored element equal to val.
+func (p *PagedSkipList[T]) Search(val T) (T, bool, error) {
+	_, _, next, err := p.descend(val)
+	if err != nil || next == nil || p.comparator(next.val, val) != 0 {
+		var zero T
+		return zero, false, err
+	}
+	return next.val, true, nil
+}
+
+// SearchByRank returns the element at the given 1-indexed rank.
+//
+// Time Complexity: O(log n) page reads on average
+func (p *PagedSkipList[T]) SearchByRank(rank int) (T, bool, error) {
+	var zero T
+	if rank < 1 || rank > p.length {
+		return zero, false, nil
+	}
+
+	curr, err := p.readNode(0)
+	if err != nil {
+		return zero, false, err
+	}
+	pos := 0
+	for lvl := p.maxLevel; lvl >= 0; lvl-- {
+		for curr.levels[lvl].next != 0 && pos+curr.levels[lvl].span <= rank {
+			pos += curr.levels[lvl].span
+			if curr, err = p.readNode(curr.levels[lvl].next); err != nil {
+				return zero, false, err
+			}
+		}
+		if pos == rank {
+			return curr.val, true, nil
+		}
+	}
+	return zero, false, nil
+}
+
+// GetRank returns the 1-indexed rank of val.
+func (p *PagedSkipList[T]) GetRank(val T) (int, bool, error) {
+	_, rank, next, err := p.descend(val)
+	if err != nil || next == nil || p.comparator(next.val, val) != 0 {
+		return -1, false, err
+	}
+	return rank[0] + 1, true, nil
+}
+
+// Range calls fn for every element in ascending order until fn returns false.
+func (p *PagedSkipList[T]) Range(fn func(val T) bool) error {
+	for page := p.head[0].next; page != 0; {
+		node, err := p.readNode(page)
+		if err != nil {
+			return err
+		}
+		if !fn(node.val) {
+			return nil
+		}
+		page = node.levels[0].next
+	}
+	return nil
+}
+
+// Len returns the number of elements in the list.
+func (p *PagedSkipList[T]) Len() int {
+	return p.length
+}
+
+// Commit writes every dirty page and the header to the file and fsyncs it.
+func (p *PagedSkipList[T]) Commit() error {
+	if err := p.pool.flush(); err != nil {
+		return err
+	}
+	if p.dirty {
+		if _, err := p.f.WriteAt(p.encodeHeader(), 0); err != nil {
+			return err
+		}
+		p.dirty = false
+	}
+	return p.f.Sync()
+}
+
+func (p *PagedSkipList[T]) commitIfSync() error {
+	if p.sync {
+		return p.Commit()
+	}
+	return nil
+}
+
+// Close commits the list. The file stays open and is the caller's to close.
+func (p *PagedSkipList[T]) Close() error {
+	return p.Commit()
+}
+
+// bufferPool caches pages in memory and evicts the least recently used one
+// when full, writing it back first if it is dirty.
+type bufferPool struct {
+	f        io.WriterAt
+	r        io.ReaderAt
+	pageSize int
+	capacity int
+	frames   map[uint64]*list.Element
+	lru      *list.List // of *frame, most recently used first
+	// hits and misses count page lookups, for tests and tuning.
+	hits, misses int
+}
+
+type frame struct {
+	page  uint64
+	data  []byte
+	dirty bool
+}
+
+func newBufferPool(f *os.File, pageSize, capacity int) *bufferPool {
+	return &bufferPool{
+		f:        f,
+		r:        f,
+		pageSize: pageSize,
+		capacity: capacity,
+		frames:   make(map[uint64]*list.Element, capacity),
+		lru:      list.New(),
+	}
+}
+
+// get returns the contents of page for reading.
+func (bp *bufferPool) get(page uint64) ([]byte, error) {
+	fr, err := bp.frame(page)
+	if err != nil {
+		return nil, err
+	}
+	return fr.data, nil
+}
+
+// getForWrite returns the contents of page and marks it dirty.
+func (bp *bufferPool) getForWrite(page uint64) ([]byte, error) {
+	fr, err := bp.frame(page)
+	if err != nil {
+		return nil, err
+	}
+	fr.dirty = true
+	return fr.data, nil
+}
+
+// create returns a zeroed, dirty page without reading it from the file.
+func (bp *bufferPool) create(page uint64) []byte {
+	if el, ok := bp.frames[page]; ok {
+		bp.lru.MoveToFront(el)
+		fr := el.Value.(*frame)
+		clear(fr.data)
+		fr.dirty = true
+		return fr.data
+	}
+	fr := &frame{page: page, data: make([]byte, bp.pageSize), dirty: true}
+	bp.insert(fr)
+	return fr.data
+}
+
+func (bp *bufferPool) frame(page uint64) (*frame, error) {
+	if el, ok := bp.frames[page]; ok {
+		bp.hits++
+		bp.lru.MoveToFront(el)
+		return el.Value.(*frame), nil
+	}
+	bp.misses++
+
+	fr := &frame{page: page, data: make([]byte, bp.pageSize)}
+	if _, err := bp.r.ReadAt(fr.data, int64(page)*int64(bp.pageSize)); err != nil {
+		return nil, err
+	}
+	bp.insert(fr)
+	return fr, nil
+}
+
+// insert adds fr to the pool. A frame evicted to make room is written back
+// first; a failed write is not fatal to the pool, so the frame is kept and the
+// pool grows past its capacity until a later eviction succeeds.
+func (bp *bufferPool) insert(fr *frame) {
+	if bp.lru.Len() >= bp.capacity {
+		victim := bp.lru.Back().Value.(*frame)
+		if bp.writeBack(victim) == nil {
+			bp.lru.Remove(bp.frames[victim.page])
+			delete(bp.frames, victim.page)
+		}
+	}
+	bp.frames[fr.page] = bp.lru.PushFront(fr)
+}
+
+func (bp *bufferPool) writeBack(fr *frame) error {
+	if !fr.dirty {
+		return nil
+	}
+	if _, err := bp.f.WriteAt(fr.data, int64(fr.page)*int64(bp.pageSize)); err != nil {
+		return err
+	}
+	fr.dirty = false
+	return nil
+}
+
+// flush writes back every dirty page.
+func (bp *bufferPool) flush() error {
+	for el := bp.lru.Front(); el != nil; el = el.Next() {
+		if err := bp.writeBack(el.Value.(*frame)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
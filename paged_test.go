@@ -0,0 +1,231 @@
+package skiplist
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Paged helpers
+// ------------------------------------------------------------
+
+func openPageFile(t *testing.T, path string) *os.File {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile returned %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func openPagedInts(t *testing.T, f *os.File, opts PagedOptions[int]) *PagedSkipList[int] {
+	t.Helper()
+	p, err := OpenPaged[int](f, opts)
+	if err != nil {
+		t.Fatalf("OpenPaged returned %v", err)
+	}
+	return p
+}
+
+func pagedValues(t *testing.T, p *PagedSkipList[int]) []int {
+	t.Helper()
+	vals := []int{}
+	if err := p.Range(func(val int) bool {
+		vals = append(vals, val)
+		return true
+	}); err != nil {
+		t.Fatalf("Range returned %v", err)
+	}
+	return vals
+}
+
+// ------------------------------------------------------------
+// PagedSkipList
+// ------------------------------------------------------------
+
+func TestPaged_MatchesModelWithEviction(t *testing.T) {
+	f := openPageFile(t, filepath.Join(t.TempDir(), "list.pg"))
+	// a cache far smaller than the list forces pages in and out of the pool
+	p := openPagedInts(t, f, PagedOptions[int]{CachePages: 8})
+	rng := rand.New(rand.NewSource(46))
+	model := map[int]bool{}
+
+	for i := 0; i < 3000; i++ {
+		v := rng.Intn(1000)
+		if rng.Intn(3) == 0 {
+			if err := p.Delete(v); err != nil {
+				t.Fatalf("Delete(%d) returned %v", v, err)
+			}
+			delete(model, v)
+		} else {
+			if err := p.Add(v); err != nil {
+				t.Fatalf("Add(%d) returned %v", v, err)
+			}
+			model[v] = true
+		}
+	}
+
+	want := modelValues(model)
+	if got := pagedValues(t, p); !slicesEqual(got, want) {
+		t.Fatalf("paged list has %d values, model has %d", len(got), len(want))
+	}
+	if p.Len() != len(want) {
+		t.Fatalf("expected Len %d, got %d", len(want), p.Len())
+	}
+	if p.pool.lru.Len() > 2*(MaxLevelCap+1) {
+		t.Fatalf("pool holds %d pages, above its capacity", p.pool.lru.Len())
+	}
+	if p.pool.misses == 0 {
+		t.Fatal("expected pages to be read back after eviction")
+	}
+
+	for rank, v := range want {
+		if got, ok, err := p.SearchByRank(rank + 1); err != nil || !ok || got != v {
+			t.Fatalf("SearchByRank(%d) = %d %v %v, want %d", rank+1, got, ok, err, v)
+		}
+		if r, ok, err := p.GetRank(v); err != nil || !ok || r != rank+1 {
+			t.Fatalf("GetRank(%d) = %d %v %v, want %d", v, r, ok, err, rank+1)
+		}
+	}
+	if _, ok, _ := p.SearchByRank(len(want) + 1); ok {
+		t.Fatal("SearchByRank past the end should not find a value")
+	}
+	if _, ok, _ := p.GetRank(-1); ok {
+		t.Fatal("GetRank of a missing value should not find it")
+	}
+}
+
+func TestPaged_ReopenKeepsContentsAndShape(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.pg")
+	f := openPageFile(t, path)
+	p := openPagedInts(t, f, PagedOptions[int]{PageSize: 1024})
+	for i := 0; i < 500; i++ {
+		p.Add(i * 2)
+	}
+	for i := 0; i < 500; i += 5 {
+		p.Delete(i * 2)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	maxLevel, levelCount := p.maxLevel, p.levelCount
+	want := pagedValues(t, p)
+	f.Close()
+
+	f = openPageFile(t, path)
+	// the page size of an existing file comes from its header
+	p = openPagedInts(t, f, PagedOptions[int]{})
+	if p.pageSize != 1024 {
+		t.Fatalf("expected page size 1024 from the header, got %d", p.pageSize)
+	}
+	if p.maxLevel != maxLevel || p.levelCount != levelCount {
+		t.Fatal("tower heights changed across reopen")
+	}
+	if got := pagedValues(t, p); !slicesEqual(got, want) {
+		t.Fatalf("values changed across reopen: %d vs %d", len(got), len(want))
+	}
+	if v, ok, err := p.Search(6); err != nil || !ok || v != 6 {
+		t.Fatalf("Search(6) = %d %v %v", v, ok, err)
+	}
+	if _, ok, _ := p.Search(10); ok {
+		t.Fatal("deleted value 10 was found after reopen")
+	}
+	if r, ok, _ := p.GetRank(6); !ok || r != 3 {
+		t.Fatalf("expected rank 3 for 6, got %d", r)
+	}
+}
+
+func TestPaged_ReusesFreedPages(t *testing.T) {
+	f := openPageFile(t, filepath.Join(t.TempDir(), "list.pg"))
+	p := openPagedInts(t, f, PagedOptions[int]{})
+	for i := 0; i < 100; i++ {
+		p.Add(i)
+	}
+	pages := p.pages
+	for i := 0; i < 100; i += 2 {
+		p.Delete(i)
+	}
+	for i := 1000; i < 1050; i++ {
+		p.Add(i)
+	}
+	if p.pages != pages {
+		t.Fatalf("expected freed pages to be reused, file grew from %d to %d pages", pages, p.pages)
+	}
+	if p.free != 0 {
+		t.Fatal("expected the free list to be used up")
+	}
+	if p.Len() != 100 {
+		t.Fatalf("expected 100 values, got %d", p.Len())
+	}
+}
+
+func TestPaged_SyncOnCommitPersistsEachWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.pg")
+	f := openPageFile(t, path)
+	p := openPagedInts(t, f, PagedOptions[int]{SyncOnCommit: true})
+	p.Add(3)
+	p.Add(1)
+	p.Add(2)
+	p.Delete(3)
+
+	// without Close, a second handle still sees every write
+	other := openPageFile(t, path)
+	q := openPagedInts(t, other, PagedOptions[int]{})
+	if got := pagedValues(t, q); !slicesEqual(got, []int{1, 2}) {
+		t.Fatalf("expected [1 2], got %v", got)
+	}
+}
+
+func TestPaged_Errors(t *testing.T) {
+	dir := t.TempDir()
+	f := openPageFile(t, filepath.Join(dir, "strings.pg"))
+	p, err := OpenPaged[string](f, PagedOptions[string]{})
+	if err != nil {
+		t.Fatalf("OpenPaged returned %v", err)
+	}
+	if err := p.Add(strings.Repeat("x", DefaultPageSize)); !errors.Is(err, ErrValueTooLarge) {
+		t.Fatalf("expected ErrValueTooLarge, got %v", err)
+	}
+	if p.Len() != 0 {
+		t.Fatal("a rejected value should not be added")
+	}
+
+	small := openPageFile(t, filepath.Join(dir, "small.pg"))
+	if _, err := OpenPaged[int](small, PagedOptions[int]{PageSize: 64}); err == nil {
+		t.Fatal("expected an error for a page size below MinPageSize")
+	}
+
+	junk := openPageFile(t, filepath.Join(dir, "junk.pg"))
+	junk.Write(make([]byte, DefaultPageSize))
+	if _, err := OpenPaged[int](junk, PagedOptions[int]{}); !errors.Is(err, ErrBadPageFile) {
+		t.Fatalf("expected ErrBadPageFile, got %v", err)
+	}
+
+	nocodec := openPageFile(t, filepath.Join(dir, "nocodec.pg"))
+	if _, err := OpenComparablePaged[account](nocodec, PagedOptions[account]{}); !errors.Is(err, ErrNoCodec) {
+		t.Fatalf("expected ErrNoCodec, got %v", err)
+	}
+}
+
+func TestPaged_ComparableWithCodec(t *testing.T) {
+	f := openPageFile(t, filepath.Join(t.TempDir(), "accounts.pg"))
+	p, err := OpenComparablePaged[account](f, PagedOptions[account]{Codec: accountCodec{}})
+	if err != nil {
+		t.Fatalf("OpenComparablePaged returned %v", err)
+	}
+	p.Add(account{"carol", 30})
+	p.Add(account{"alice", 10})
+	p.Add(account{"bob", 20})
+
+	if got, ok, err := p.Search(account{id: "bob"}); err != nil || !ok || got.balance != 20 {
+		t.Fatalf("Search(bob) = %v %v %v", got, ok, err)
+	}
+	if got, _, _ := p.SearchByRank(1); got.id != "alice" {
+		t.Fatalf("expected alice first, got %v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package skiplist
+
+// Bytes is the constraint for element types that support prefix scans.
+type Bytes interface {
+	~string | ~[]byte
+}
+
+// PrefixRange iterates in ascending order over the elements that start with
+// prefix. The function fn is called for each element. If fn returns false,
+// iteration stops.
+//
+// The scan seeks to the first candidate with the same descent as GetLowerBound
+// and stops at the prefix's successor, so it costs O(log n + k) for k matches.
+// The list must be ordered bytewise, as lists created by NewSkipList are.
+func PrefixRange[T Bytes](sl *SkipList[T], prefix T, fn func(val T) bool) {
+	node, _ := sl.seek(beforePrefix(sl, prefix))
+	inPrefix := beforePrefixEnd(sl, prefix)
+
+	for ; node != nil && inPrefix(node.val); node = node.levels[0].next {
+		if !fn(node.val) {
+			return
+		}
+	}
+}
+
+// CountPrefix returns the number of elements that start with prefix.
+//
+// Time Complexity: O(log n) average
+func CountPrefix[T Bytes](sl *SkipList[T], prefix T) int {
+	_, first := sl.seek(beforePrefix(sl, prefix))
+	_, end := sl.seek(beforePrefixEnd(sl, prefix))
+	return end - first
+}
+
+// DeletePrefix removes every element that starts with prefix and returns how
+// many were removed. The matching run is unlinked in one pass per level.
+//
+// Time Complexity: O(log n + k) average, where k is the number of removed elements
+func DeletePrefix[T Bytes](sl *SkipList[T], prefix T) int {
+	return sl.deleteBetween(beforePrefix(sl, prefix), beforePrefixEnd(sl, prefix))
+}
+
+// beforePrefix reports whether a value sorts before every value with the prefix.
+func beforePrefix[T Bytes](sl *SkipList[T], prefix T) func(T) bool {
+	return func(v T) bool {
+		return sl.comparator(v, prefix) < 0
+	}
+}
+
+// beforePrefixEnd reports whether a value sorts before the prefix's successor,
+// the smallest value greater than every value with the prefix.
+func beforePrefixEnd[T Bytes](sl *SkipList[T], prefix T) func(T) bool {
+	succ, ok := prefixSuccessor([]byte(prefix))
+	if !ok {
+		return func(T) bool { return true }
+	}
+
+	end := T(succ)
+	return func(v T) bool {
+		return sl.comparator(v, end) < 0
+	}
+}
+
+// prefixSuccessor returns the smallest byte string greater than every string
+// starting with prefix: the prefix with trailing 0xFF bytes dropped and its
+// last byte incremented. It reports false if no such string exists, which is
+// the case for an empty prefix or one made only of 0xFF bytes.
+func prefixSuccessor(prefix []byte) ([]byte, bool) {
+	n := len(prefix)
+	for n > 0 && prefix[n-1] == 0xFF {
+		n--
+	}
+	if n == 0 {
+		return nil, false
+	}
+
+	succ := make([]byte, n)
+	copy(succ, prefix[:n])
+	succ[n-1]++
+	return succ, true
+}
@@ -0,0 +1,166 @@
+package skiplist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Prefix scan helpers
+// ------------------------------------------------------------
+
+func collectPrefix[T Bytes](sl *SkipList[T], prefix T) []string {
+	var out []string
+	PrefixRange(sl, prefix, func(v T) bool {
+		out = append(out, string(v))
+		return true
+	})
+	return out
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func newKeyspace() *SkipList[string] {
+	sl := NewSkipList[string]()
+	for _, k := range []string{
+		"user:1", "user:12", "user:123:email", "user:123:name", "user:124",
+		"user;", "users", "order:9", "",
+	} {
+		sl.Add(k)
+	}
+	return sl
+}
+
+// ------------------------------------------------------------
+// Prefix scan tests
+// ------------------------------------------------------------
+
+func TestPrefixSuccessor(t *testing.T) {
+	tests := []struct {
+		prefix []byte
+		want   []byte
+		ok     bool
+	}{
+		{[]byte("abc"), []byte("abd"), true},
+		{[]byte("ab\xff"), []byte("ac"), true},
+		{[]byte("a\xff\xff"), []byte("b"), true},
+		{[]byte("\xff\xff"), nil, false},
+		{[]byte{}, nil, false},
+		{[]byte{0x00}, []byte{0x01}, true},
+	}
+
+	for _, tc := range tests {
+		got, ok := prefixSuccessor(tc.prefix)
+		if ok != tc.ok || !bytes.Equal(got, tc.want) {
+			t.Fatalf("prefixSuccessor(%q): got %q (%v), want %q (%v)", tc.prefix, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestPrefixRange_Strings(t *testing.T) {
+	sl := newKeyspace()
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"user:123:", []string{"user:123:email", "user:123:name"}},
+		{"user:12", []string{"user:12", "user:123:email", "user:123:name", "user:124"}},
+		{"user:", []string{"user:1", "user:12", "user:123:email", "user:123:name", "user:124"}},
+		{"order", []string{"order:9"}},
+		{"zzz", nil},
+	}
+
+	for _, tc := range tests {
+		if got := collectPrefix(sl, tc.prefix); !stringsEqual(got, tc.want) {
+			t.Fatalf("PrefixRange(%q): got %q, want %q", tc.prefix, got, tc.want)
+		}
+		if got := CountPrefix(sl, tc.prefix); got != len(tc.want) {
+			t.Fatalf("CountPrefix(%q): got %d, want %d", tc.prefix, got, len(tc.want))
+		}
+	}
+
+	if got := CountPrefix(sl, ""); got != sl.Len() {
+		t.Fatalf("empty prefix should match everything, got %d of %d", got, sl.Len())
+	}
+}
+
+func TestPrefixRange_StopsEarly(t *testing.T) {
+	sl := newKeyspace()
+
+	calls := 0
+	PrefixRange(sl, "user:", func(string) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("fn returning false should stop iteration, got %d calls", calls)
+	}
+}
+
+func TestPrefixRange_ByteSlicesWithFF(t *testing.T) {
+	sl := newSkipList(bytes.Compare)
+	keys := [][]byte{
+		{0x01, 0xFE}, {0x01, 0xFF}, {0x01, 0xFF, 0x00}, {0x01, 0xFF, 0xFF},
+		{0x02}, {0xFF}, {0xFF, 0xFF, 0x01},
+	}
+	for _, k := range keys {
+		sl.Add(k)
+	}
+
+	if got := CountPrefix(sl, []byte{0x01, 0xFF}); got != 3 {
+		t.Fatalf("CountPrefix(01 ff): got %d, want 3", got)
+	}
+	if got := CountPrefix(sl, []byte{0xFF}); got != 2 {
+		t.Fatalf("CountPrefix(ff) has no successor and should scan to the end: got %d, want 2", got)
+	}
+	if got := collectPrefix(sl, []byte{0xFF, 0xFF}); len(got) != 1 || got[0] != "\xff\xff\x01" {
+		t.Fatalf("PrefixRange(ff ff): got %q", got)
+	}
+}
+
+func TestDeletePrefix(t *testing.T) {
+	sl := NewSkipList[string]()
+	var survivors []string
+	for i := 0; i < 500; i++ {
+		k := strings.Repeat("x", i%7) + string(rune('a'+i%26))
+		sl.Add(k)
+	}
+	sl.Range(func(k string) bool {
+		if !strings.HasPrefix(k, "xxx") {
+			survivors = append(survivors, k)
+		}
+		return true
+	})
+
+	before := sl.Len()
+	if removed := DeletePrefix(sl, "xxx"); removed != before-len(survivors) {
+		t.Fatalf("DeletePrefix removed %d, want %d", removed, before-len(survivors))
+	}
+	if sl.Len() != len(survivors) {
+		t.Fatalf("Len after DeletePrefix: got %d, want %d", sl.Len(), len(survivors))
+	}
+	if CountPrefix(sl, "xxx") != 0 {
+		t.Fatal("no element with the prefix should remain")
+	}
+
+	for i, k := range survivors {
+		if rank, found := sl.GetRank(k); !found || rank != i+1 {
+			t.Fatalf("rank of %q after DeletePrefix: got %d, want %d", k, rank, i+1)
+		}
+	}
+	if DeletePrefix(sl, "xxx") != 0 {
+		t.Fatal("deleting an absent prefix should remove nothing")
+	}
+}
@@ -394,7 +394,7 @@ func TestSearchByRank_ConsistencyWithOrder(t *testing.T) {
 
 	// Get all nodes in order by traversing level 0
 	var orderedValues []int
-	for curr := s.head.forward[0]; curr != s.tail; curr = curr.forward[0] {
+	for curr := s.head.levels[0].next; curr != s.tail; curr = curr.levels[0].next {
 		orderedValues = append(orderedValues, curr.val)
 	}
 
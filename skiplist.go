@@ -42,10 +42,20 @@ type Comparable[T any] interface {
 	Compare(other T) int
 }
 
+// level is one rung of a node's tower: the next node at that level and the
+// number of level-0 steps the link covers. Keeping the two side by side means a
+// search step reads both from the same cache line.
+//
+// span is kept as an int rather than narrowed to int32: next is pointer-aligned,
+// so an int32 span would be padded out to the same 16 bytes on 64-bit platforms.
+type level[T any] struct {
+	next *Node[T]
+	span int
+}
+
 type Node[T any] struct {
-	val     T
-	skips   []int
-	forward []*Node[T]
+	val    T
+	levels []level[T]
 }
 
 // Value returns the value stored in the node.
@@ -54,10 +64,10 @@ func (n *Node[T]) Value() T {
 }
 
 func (n *Node[T]) GetNextNodeAtLevel(level int) *Node[T] {
-	if level >= len(n.forward) {
+	if level >= len(n.levels) {
 		return nil
 	}
-	return n.forward[level]
+	return n.levels[level].next
 }
 
 type SkipList[T any] struct {
@@ -67,23 +77,92 @@ type SkipList[T any] struct {
 	length     int
 	levelCount [MaxLevelCap + 1]int
 	comparator Comparator[T]
+	arena      *nodeArena[T]
+	codec      Codec[T]
 }
 
-func NewNode[T any](val T, forwards int) *Node[T] {
-	return &Node[T]{
-		val:     val,
-		forward: make([]*Node[T], forwards),
-		skips:   make([]int, forwards),
+// Nodes are allocated together with their tower in one of these blocks, sized
+// to the smallest class that fits. With p = 0.5, towers of four levels or fewer
+// account for almost 94% of the nodes.
+type (
+	nodeBlock1[T any] struct {
+		node  Node[T]
+		tower [1]level[T]
+	}
+	nodeBlock2[T any] struct {
+		node  Node[T]
+		tower [2]level[T]
+	}
+	nodeBlock3[T any] struct {
+		node  Node[T]
+		tower [3]level[T]
+	}
+	nodeBlock4[T any] struct {
+		node  Node[T]
+		tower [4]level[T]
+	}
+	nodeBlock8[T any] struct {
+		node  Node[T]
+		tower [8]level[T]
+	}
+	nodeBlockMax[T any] struct {
+		node  Node[T]
+		tower [MaxLevelCap + 1]level[T]
+	}
+)
+
+// NewNode creates a node with a tower of the given height. The node and its
+// tower share a single allocation.
+func NewNode[T any](val T, height int) *Node[T] {
+	var node *Node[T]
+	var tower []level[T]
+
+	switch {
+	case height <= 1:
+		b := new(nodeBlock1[T])
+		node, tower = &b.node, b.tower[:]
+	case height == 2:
+		b := new(nodeBlock2[T])
+		node, tower = &b.node, b.tower[:]
+	case height == 3:
+		b := new(nodeBlock3[T])
+		node, tower = &b.node, b.tower[:]
+	case height == 4:
+		b := new(nodeBlock4[T])
+		node, tower = &b.node, b.tower[:]
+	case height <= 8:
+		b := new(nodeBlock8[T])
+		node, tower = &b.node, b.tower[:]
+	case height <= MaxLevelCap+1:
+		b := new(nodeBlockMax[T])
+		node, tower = &b.node, b.tower[:]
+	default:
+		node, tower = new(Node[T]), make([]level[T], height)
 	}
+
+	node.val = val
+	node.levels = tower[:height:height]
+	return node
 }
 
 func NewSkipList[T cmp.Ordered]() *SkipList[T] {
+	return newSkipList(cmp.Compare[T])
+}
+
+func NewComparableSkipList[T Comparable[T]]() *SkipList[T] {
+	return newSkipList(func(a, b T) int {
+		return a.Compare(b)
+	})
+}
+
+// newSkipList creates an empty skip list ordered by comparator.
+func newSkipList[T any](comparator Comparator[T]) *SkipList[T] {
 	var zero T
 	first := NewNode(zero, MaxLevelCap+1)
 
 	for i := 0; i <= MaxLevelCap; i++ {
-		first.forward[i] = nil
-		first.skips[i] = 1
+		first.levels[i].next = nil
+		first.levels[i].span = 1
 	}
 
 	return &SkipList[T]{
@@ -91,27 +170,7 @@ func NewSkipList[T cmp.Ordered]() *SkipList[T] {
 		tail:       nil,
 		maxLevel:   0,
 		length:     0,
-		comparator: cmp.Compare[T],
-	}
-}
-
-func NewComparableSkipList[T Comparable[T]]() *SkipList[T] {
-	var zero T
-	first := NewNode(zero, MaxLevelCap+1)
-
-	for i := 0; i <= MaxLevelCap; i++ {
-		first.forward[i] = nil
-		first.skips[i] = 1
-	}
-
-	return &SkipList[T]{
-		head:     first,
-		tail:     nil,
-		maxLevel: 0,
-		length:   0,
-		comparator: func(a, b T) int {
-			return a.Compare(b)
-		},
+		comparator: comparator,
 	}
 }
 
@@ -128,95 +187,131 @@ func (sl *SkipList[T]) Add(val T) {
 }
 
 func (sl *SkipList[T]) InsertAtLevel(val T, lvl int) {
-	hierarchy := [MaxLevelCap + 1]*Node[T]{}
-	rank := [MaxLevelCap + 1]int{}
-	curr := sl.head
-	skipped := 0
+	var f finger[T]
+	sl.descend(&f, val, sl.maxLevel)
 
-	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
-		for curr.forward[currLevel] != nil && sl.comparator(curr.forward[currLevel].val, val) <= 0 {
-			skipped += curr.skips[currLevel]
-			curr = curr.forward[currLevel]
-		}
+	// do nothing if the value is already added
+	if next := f.hierarchy[0].levels[0].next; next != nil && sl.comparator(next.val, val) == 0 {
+		return
+	}
 
-		// do nothing if the value is already added
-		if curr != sl.head && sl.comparator(curr.val, val) == 0 {
-			return
+	sl.link(&f, val, lvl)
+}
+
+func (sl *SkipList[T]) Delete(val T) {
+	var f finger[T]
+	sl.descend(&f, val, sl.maxLevel)
+
+	// only a node holding val can be removed
+	nodeToDelete := f.hierarchy[0].levels[0].next
+	if nodeToDelete == nil || sl.comparator(nodeToDelete.val, val) != 0 {
+		return
+	}
+
+	sl.unlink(&f, nodeToDelete)
+}
+
+// finger records, for every level, the last node before a search target and
+// that node's rank: the hierarchy an insert or delete splices at. Kept across
+// operations in ascending order, it lets each search resume where the
+// previous one ended instead of starting from the head.
+type finger[T any] struct {
+	hierarchy [MaxLevelCap + 1]*Node[T]
+	rank      [MaxLevelCap + 1]int
+}
+
+// descend moves f onto the last node before val at every level from top down
+// to 0. Searching from the head, f starts empty and top is maxLevel. A finger
+// left by an earlier, smaller target is reused: the search starts from its
+// node at top, and at each lower level from whichever of the current node and
+// the finger's node is further along.
+func (sl *SkipList[T]) descend(f *finger[T], val T, top int) {
+	curr, rank := f.hierarchy[top], f.rank[top]
+	if curr == nil {
+		curr = sl.head
+	}
+
+	for currLevel := top; currLevel >= 0; currLevel-- {
+		if f.hierarchy[currLevel] != nil && f.rank[currLevel] > rank {
+			curr, rank = f.hierarchy[currLevel], f.rank[currLevel]
+		}
+		for curr.levels[currLevel].next != nil && sl.comparator(curr.levels[currLevel].next.val, val) < 0 {
+			rank += curr.levels[currLevel].span
+			curr = curr.levels[currLevel].next
 		}
 
-		hierarchy[currLevel] = curr
-		rank[currLevel] = skipped
+		f.hierarchy[currLevel] = curr
+		f.rank[currLevel] = rank
 	}
+}
 
+// link inserts val with a tower of height lvl+1 after the nodes recorded in f,
+// which must be positioned on val by descend, and moves f onto the new node.
+func (sl *SkipList[T]) link(f *finger[T], val T, lvl int) *Node[T] {
 	if lvl > sl.maxLevel {
 		for i := sl.maxLevel + 1; i <= lvl; i++ {
-			hierarchy[i] = sl.head
-			rank[i] = 0
+			f.hierarchy[i] = sl.head
+			f.rank[i] = 0
 		}
 
 		sl.maxLevel = lvl
 	}
 
-	newNode := NewNode(val, lvl+1)
+	skipped := f.rank[0]
+	newNode := sl.newNode(val, lvl+1)
 
 	for i := 0; i <= lvl; i++ {
-		newNode.forward[i] = hierarchy[i].forward[i]
-		hierarchy[i].forward[i] = newNode
+		pred := f.hierarchy[i]
+		newNode.levels[i].next = pred.levels[i].next
+		pred.levels[i].next = newNode
 
-		newNode.skips[i] = rank[i] + hierarchy[i].skips[i] - skipped
-		hierarchy[i].skips[i] = skipped - rank[i] + 1
+		newNode.levels[i].span = f.rank[i] + pred.levels[i].span - skipped
+		pred.levels[i].span = skipped - f.rank[i] + 1
 
+		f.hierarchy[i] = newNode
+		f.rank[i] = skipped + 1
 		sl.levelCount[i]++
 	}
 
 	for i := lvl + 1; i <= sl.maxLevel; i++ {
-		hierarchy[i].skips[i]++
+		f.hierarchy[i].levels[i].span++
 	}
 	for i := sl.maxLevel + 1; i <= MaxLevelCap; i++ {
-		sl.head.skips[i]++
+		sl.head.levels[i].span++
 	}
 
 	sl.length++
+	return newNode
 }
 
-func (sl *SkipList[T]) Delete(val T) {
-	hierarchy := [MaxLevelCap + 1]*Node[T]{}
-	curr := sl.head
-	var nodeToDelete *Node[T] = nil
+// unlink removes nodeToDelete, whose predecessors must be recorded in f by
+// descend. f stays valid for later, larger targets.
+func (sl *SkipList[T]) unlink(f *finger[T], nodeToDelete *Node[T]) {
+	height := len(nodeToDelete.levels)
 
-	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
-		for curr.forward[currLevel] != nil && sl.comparator(curr.forward[currLevel].val, val) < 0 {
-			curr = curr.forward[currLevel]
-		}
-
-		nodeToDelete = curr.forward[currLevel]
-		if nodeToDelete != nil && sl.comparator(nodeToDelete.val, val) == 0 {
-			curr.skips[currLevel] += nodeToDelete.skips[currLevel] - 1
-			curr.forward[currLevel] = nodeToDelete.forward[currLevel]
-			nodeToDelete.forward[currLevel] = nil
-
-			sl.levelCount[currLevel]--
-			if sl.levelCount[currLevel] == 0 {
-				sl.maxLevel--
-			}
-		} else {
-			hierarchy[currLevel] = curr
-		}
-	}
+	for i := 0; i < height; i++ {
+		pred := f.hierarchy[i]
+		pred.levels[i].span += nodeToDelete.levels[i].span - 1
+		pred.levels[i].next = nodeToDelete.levels[i].next
+		nodeToDelete.levels[i].next = nil
 
-	// if the node to delete was found only then reduce the span of the remaining hierarchy
-	if nodeToDelete == nil {
-		return
+		sl.levelCount[i]--
 	}
 
-	currLevel := len(nodeToDelete.skips)
+	// the remaining hierarchy now skips one node less
+	currLevel := height
 	for ; currLevel <= sl.maxLevel; currLevel++ {
-		hierarchy[currLevel].skips[currLevel]--
+		f.hierarchy[currLevel].levels[currLevel].span--
 	}
 	for ; currLevel <= MaxLevelCap; currLevel++ {
-		sl.head.skips[currLevel]--
+		sl.head.levels[currLevel].span--
+	}
+
+	for sl.maxLevel > 0 && sl.levelCount[sl.maxLevel] == 0 {
+		sl.maxLevel--
 	}
 
+	sl.releaseNode(nodeToDelete)
 	sl.length--
 }
 
@@ -224,8 +319,8 @@ func (sl *SkipList[T]) SearchByValue(val T) (*Node[T], bool) {
 	curr := sl.head
 
 	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
-		for curr.forward[currLevel] != nil && sl.comparator(curr.forward[currLevel].val, val) <= 0 {
-			curr = curr.forward[currLevel]
+		for curr.levels[currLevel].next != nil && sl.comparator(curr.levels[currLevel].next.val, val) <= 0 {
+			curr = curr.levels[currLevel].next
 		}
 
 		if curr != sl.head && sl.comparator(curr.val, val) == 0 {
@@ -244,9 +339,9 @@ func (sl *SkipList[T]) SearchByRank(rank int) (*Node[T], bool) {
 	rankUntil := 0
 
 	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
-		for curr != nil && rankUntil+curr.skips[currLevel] <= rank {
-			rankUntil += curr.skips[currLevel]
-			curr = curr.forward[currLevel]
+		for curr != nil && rankUntil+curr.levels[currLevel].span <= rank {
+			rankUntil += curr.levels[currLevel].span
+			curr = curr.levels[currLevel].next
 		}
 
 		if rankUntil == rank {
@@ -260,13 +355,13 @@ func (sl *SkipList[T]) GetLowerBound(val T) (*Node[T], bool) {
 	curr := sl.head
 
 	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
-		for curr.forward[currLevel] != nil && sl.comparator(curr.forward[currLevel].val, val) < 0 {
-			curr = curr.forward[currLevel]
+		for curr.levels[currLevel].next != nil && sl.comparator(curr.levels[currLevel].next.val, val) < 0 {
+			curr = curr.levels[currLevel].next
 		}
 	}
 
 	// move to the next node
-	curr = curr.forward[0]
+	curr = curr.levels[0].next
 
 	if curr != nil {
 		return curr, true
@@ -280,9 +375,9 @@ func (sl *SkipList[T]) GetRank(item T) (int, bool) {
 	rank := 0
 
 	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
-		for curr.forward[currLevel] != nil && sl.comparator(curr.forward[currLevel].val, item) <= 0 {
-			rank += curr.skips[currLevel]
-			curr = curr.forward[currLevel]
+		for curr.levels[currLevel].next != nil && sl.comparator(curr.levels[currLevel].next.val, item) <= 0 {
+			rank += curr.levels[currLevel].span
+			curr = curr.levels[currLevel].next
 		}
 
 		if curr != sl.head && sl.comparator(curr.val, item) == 0 {
@@ -292,6 +387,120 @@ func (sl *SkipList[T]) GetRank(item T) (int, bool) {
 	return -1, false
 }
 
+// seek returns the first node whose value does not satisfy before, together
+// with its rank. before must hold for a prefix of the list and fail for the
+// rest, like a "less than target" test. If every value satisfies before, seek
+// returns nil and Len()+1.
+func (sl *SkipList[T]) seek(before func(T) bool) (*Node[T], int) {
+	curr := sl.head
+	rank := 0
+
+	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil && before(curr.levels[currLevel].next.val) {
+			rank += curr.levels[currLevel].span
+			curr = curr.levels[currLevel].next
+		}
+	}
+
+	return curr.levels[0].next, rank + 1
+}
+
+// lastBefore returns the last node whose value satisfies before, or nil if no
+// value does. before must hold for a prefix of the list, as in seek.
+func (sl *SkipList[T]) lastBefore(before func(T) bool) *Node[T] {
+	curr := sl.head
+
+	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil && before(curr.levels[currLevel].next.val) {
+			curr = curr.levels[currLevel].next
+		}
+	}
+
+	if curr == sl.head {
+		return nil
+	}
+	return curr
+}
+
+// deleteBetween removes every value v for which lo(v) is false and hi(v) is
+// true, i.e. the values from the first one not before the range start up to,
+// but excluding, the first one not before the range end. Both predicates must
+// hold for a prefix of the list, as in seek. The removed run is spliced out in
+// a single pass per level and the number of removed values is returned.
+func (sl *SkipList[T]) deleteBetween(lo, hi func(T) bool) int {
+	loHierarchy := [MaxLevelCap + 1]*Node[T]{}
+	hiHierarchy := [MaxLevelCap + 1]*Node[T]{}
+	loRank := [MaxLevelCap + 1]int{}
+	hiRank := [MaxLevelCap + 1]int{}
+
+	curr, rank := sl.head, 0
+	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil && lo(curr.levels[currLevel].next.val) {
+			rank += curr.levels[currLevel].span
+			curr = curr.levels[currLevel].next
+		}
+		loHierarchy[currLevel], loRank[currLevel] = curr, rank
+	}
+
+	curr, rank = sl.head, 0
+	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil && hi(curr.levels[currLevel].next.val) {
+			rank += curr.levels[currLevel].span
+			curr = curr.levels[currLevel].next
+		}
+		hiHierarchy[currLevel], hiRank[currLevel] = curr, rank
+	}
+
+	removed := hiRank[0] - loRank[0]
+	if removed <= 0 {
+		return 0
+	}
+
+	first, end := loHierarchy[0].levels[0].next, hiHierarchy[0].levels[0].next
+	for node := first; node != end; node = node.levels[0].next {
+		for i := range node.levels {
+			sl.levelCount[i]--
+		}
+	}
+
+	for i := 0; i <= sl.maxLevel; i++ {
+		// distance from the lower predecessor to the first survivor, minus the removed run
+		span := hiRank[i] + hiHierarchy[i].levels[i].span - loRank[i] - removed
+		loHierarchy[i].levels[i].next = hiHierarchy[i].levels[i].next
+		loHierarchy[i].levels[i].span = span
+	}
+	for i := sl.maxLevel + 1; i <= MaxLevelCap; i++ {
+		sl.head.levels[i].span -= removed
+	}
+
+	for sl.maxLevel > 0 && sl.levelCount[sl.maxLevel] == 0 {
+		sl.maxLevel--
+	}
+	for node := first; node != end; {
+		next := node.levels[0].next
+		sl.releaseNode(node)
+		node = next
+	}
+	sl.length -= removed
+	return removed
+}
+
+// last returns the node holding the largest value, or nil if the list is empty.
+func (sl *SkipList[T]) last() *Node[T] {
+	curr := sl.head
+
+	for currLevel := sl.maxLevel; currLevel >= 0; currLevel-- {
+		for curr.levels[currLevel].next != nil {
+			curr = curr.levels[currLevel].next
+		}
+	}
+
+	if curr == sl.head {
+		return nil
+	}
+	return curr
+}
+
 // Len returns the number of elements in the skip list.
 func (sl *SkipList[T]) Len() int {
 	return sl.length
@@ -306,7 +515,7 @@ func (sl *SkipList[T]) Contains(val T) bool {
 // Range iterates over all elements in the skip list in ascending order.
 // The function fn is called for each element. If fn returns false, iteration stops.
 func (sl *SkipList[T]) Range(fn func(val T) bool) {
-	for curr := sl.head.forward[0]; curr != nil && curr != sl.tail; curr = curr.forward[0] {
+	for curr := sl.head.levels[0].next; curr != nil && curr != sl.tail; curr = curr.levels[0].next {
 		if !fn(curr.val) {
 			break
 		}
@@ -315,11 +524,19 @@ func (sl *SkipList[T]) Range(fn func(val T) bool) {
 
 // Clear removes all elements from the skip list.
 func (sl *SkipList[T]) Clear() {
+	if sl.arena != nil {
+		for node := sl.head.levels[0].next; node != nil; {
+			next := node.levels[0].next
+			sl.releaseNode(node)
+			node = next
+		}
+	}
+
 	var zero T
 	sl.head = NewNode(zero, MaxLevelCap+1)
 	for i := 0; i <= MaxLevelCap; i++ {
-		sl.head.forward[i] = nil
-		sl.head.skips[i] = 1
+		sl.head.levels[i].next = nil
+		sl.head.levels[i].span = 1
 	}
 	sl.tail = nil
 	sl.maxLevel = 0
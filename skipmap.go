@@ -0,0 +1,193 @@
+package skiplist
+
+import (
+	"bytes"
+	"cmp"
+)
+
+// KeyOwnership decides who owns the byte slices used as keys in a bytes map.
+type KeyOwnership int
+
+const (
+	// CopyKeys copies a key when it is first inserted, so callers may reuse or
+	// modify their buffers afterwards.
+	CopyKeys KeyOwnership = iota
+	// BorrowKeys stores the caller's buffer as is. The caller must treat every
+	// inserted key as immutable for as long as it is in the map.
+	BorrowKeys
+)
+
+type mapEntry[K, V any] struct {
+	key   K
+	value V
+}
+
+// SkipMap is an ordered map backed by a skip list of key/value entries. Keys
+// are unique and ordered by the map's comparator; lookups take the key itself
+// as the probe, so they don't allocate.
+type SkipMap[K, V any] struct {
+	list *SkipList[mapEntry[K, V]]
+	// cloneKey, if set, is applied to a key before it is stored.
+	cloneKey func(K) K
+	// keyCodec and valueCodec, if set, encode entries for sorted tables.
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+}
+
+// NewSkipMap creates an ordered map for ordered key types.
+func NewSkipMap[K cmp.Ordered, V any]() *SkipMap[K, V] {
+	return NewSkipMapFunc[K, V](cmp.Compare[K])
+}
+
+// NewSkipMapFunc creates an ordered map whose keys are ordered by comparator.
+func NewSkipMapFunc[K, V any](comparator Comparator[K]) *SkipMap[K, V] {
+	return &SkipMap[K, V]{
+		list: newSkipList(func(a, b mapEntry[K, V]) int {
+			return comparator(a.key, b.key)
+		}),
+	}
+}
+
+// NewBytesMap creates an ordered map with []byte keys compared by bytes.Compare,
+// without converting keys to strings. ownership decides whether inserted keys
+// are copied or borrowed from the caller.
+func NewBytesMap[V any](ownership KeyOwnership) *SkipMap[[]byte, V] {
+	m := NewSkipMapFunc[[]byte, V](bytes.Compare)
+	if ownership == CopyKeys {
+		m.cloneKey = bytes.Clone
+	}
+	return m
+}
+
+// NewBytesSkipList creates a skip list of []byte values compared by
+// bytes.Compare. The list borrows the inserted slices, so they must not be
+// modified while they are in the list.
+func NewBytesSkipList() *SkipList[[]byte] {
+	return newSkipList(bytes.Compare)
+}
+
+// Set associates value with key, replacing the value of an existing key. An
+// existing key keeps its stored copy; only new keys are cloned.
+func (m *SkipMap[K, V]) Set(key K, value V) {
+	if node, found := m.list.SearchByValue(mapEntry[K, V]{key: key}); found {
+		node.val.value = value
+		return
+	}
+
+	if m.cloneKey != nil {
+		key = m.cloneKey(key)
+	}
+	m.list.Add(mapEntry[K, V]{key: key, value: value})
+}
+
+// Get returns the value stored for key.
+func (m *SkipMap[K, V]) Get(key K) (V, bool) {
+	if node, found := m.list.SearchByValue(mapEntry[K, V]{key: key}); found {
+		return node.val.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has checks if key exists in the map.
+func (m *SkipMap[K, V]) Has(key K) bool {
+	return m.list.Contains(mapEntry[K, V]{key: key})
+}
+
+// Delete removes key from the map and reports whether it existed.
+func (m *SkipMap[K, V]) Delete(key K) bool {
+	before := m.list.Len()
+	m.list.Delete(mapEntry[K, V]{key: key})
+	return m.list.Len() < before
+}
+
+// Seek returns the first entry whose key is greater than or equal to key.
+func (m *SkipMap[K, V]) Seek(key K) (K, V, bool) {
+	if node, found := m.list.GetLowerBound(mapEntry[K, V]{key: key}); found {
+		return node.val.key, node.val.value, true
+	}
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// GetRank returns the 1-indexed position of key in the map.
+func (m *SkipMap[K, V]) GetRank(key K) (int, bool) {
+	return m.list.GetRank(mapEntry[K, V]{key: key})
+}
+
+// SearchByRank returns the entry at the given 1-indexed position.
+func (m *SkipMap[K, V]) SearchByRank(rank int) (K, V, bool) {
+	if node, found := m.list.SearchByRank(rank); found {
+		return node.val.key, node.val.value, true
+	}
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Len returns the number of entries in the map.
+func (m *SkipMap[K, V]) Len() int {
+	return m.list.Len()
+}
+
+// Range iterates over all entries in ascending key order.
+// The function fn is called for each entry. If fn returns false, iteration stops.
+// Keys passed to fn are the stored keys and must not be modified.
+func (m *SkipMap[K, V]) Range(fn func(key K, value V) bool) {
+	m.list.Range(func(e mapEntry[K, V]) bool {
+		return fn(e.key, e.value)
+	})
+}
+
+// Clear removes all entries from the map.
+func (m *SkipMap[K, V]) Clear() {
+	m.list.Clear()
+}
+
+// NewIterator returns an iterator over the map in ascending key order. The map
+// must not be modified while the iterator is in use.
+func (m *SkipMap[K, V]) NewIterator() *MapIterator[K, V] {
+	return &MapIterator[K, V]{m: m}
+}
+
+// MapIterator walks the entries of a SkipMap. It implements Iterator.
+type MapIterator[K, V any] struct {
+	m    *SkipMap[K, V]
+	node *Node[mapEntry[K, V]]
+}
+
+var _ Iterator[int, int] = (*MapIterator[int, int])(nil)
+
+// First positions the iterator at the smallest key.
+func (it *MapIterator[K, V]) First() {
+	it.node = it.m.list.head.levels[0].next
+}
+
+// Seek positions the iterator at the first key greater than or equal to key.
+func (it *MapIterator[K, V]) Seek(key K) {
+	it.node, _ = it.m.list.GetLowerBound(mapEntry[K, V]{key: key})
+}
+
+// Next moves the iterator to the next key. It is a no-op if the iterator is
+// not Valid.
+func (it *MapIterator[K, V]) Next() {
+	if it.node != nil {
+		it.node = it.node.levels[0].next
+	}
+}
+
+// Valid reports whether the iterator is positioned at an entry.
+func (it *MapIterator[K, V]) Valid() bool {
+	return it.node != nil
+}
+
+// Key returns the current key.
+func (it *MapIterator[K, V]) Key() K {
+	return it.node.val.key
+}
+
+// Value returns the current value.
+func (it *MapIterator[K, V]) Value() V {
+	return it.node.val.value
+}
@@ -0,0 +1,147 @@
+package skiplist
+
+import (
+	"bytes"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// SkipMap tests
+// ------------------------------------------------------------
+
+func TestSkipMap_SetGetDelete(t *testing.T) {
+	m := NewSkipMap[string, int]()
+	m.Set("b", 2)
+	m.Set("a", 1)
+	m.Set("c", 3)
+	m.Set("b", 20)
+
+	if m.Len() != 3 {
+		t.Fatalf("Len should be 3, got %d", m.Len())
+	}
+	if v, ok := m.Get("b"); !ok || v != 20 {
+		t.Fatalf("Get(b): got %d (%v), want 20", v, ok)
+	}
+	if _, ok := m.Get("z"); ok {
+		t.Fatal("Get of missing key should report false")
+	}
+
+	var keys []string
+	m.Range(func(k string, _ int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if !stringsEqual(keys, []string{"a", "b", "c"}) {
+		t.Fatalf("Range order: got %v", keys)
+	}
+
+	if !m.Delete("a") || m.Delete("a") {
+		t.Fatal("Delete should report true once, then false")
+	}
+	if k, v, ok := m.SearchByRank(1); !ok || k != "b" || v != 20 {
+		t.Fatalf("SearchByRank(1): got %q=%d (%v)", k, v, ok)
+	}
+	if k, _, ok := m.Seek("bb"); !ok || k != "c" {
+		t.Fatalf("Seek(bb): got %q (%v), want c", k, ok)
+	}
+}
+
+func TestBytesMap_CopyKeys(t *testing.T) {
+	m := NewBytesMap[int](CopyKeys)
+
+	buf := []byte("key-1")
+	m.Set(buf, 1)
+	buf[4] = '9' // caller reuses its buffer
+
+	if _, ok := m.Get([]byte("key-1")); !ok {
+		t.Fatal("copied key should be unaffected by caller mutation")
+	}
+	if _, ok := m.Get([]byte("key-9")); ok {
+		t.Fatal("mutated caller buffer must not be visible in the map")
+	}
+}
+
+func TestBytesMap_BorrowKeys(t *testing.T) {
+	m := NewBytesMap[int](BorrowKeys)
+
+	buf := []byte("key-1")
+	m.Set(buf, 1)
+
+	var stored []byte
+	m.Range(func(k []byte, _ int) bool {
+		stored = k
+		return false
+	})
+	if &stored[0] != &buf[0] {
+		t.Fatal("borrowed key should be stored without copying")
+	}
+}
+
+func TestBytesMap_LookupsDoNotAllocate(t *testing.T) {
+	m := NewBytesMap[int](CopyKeys)
+	for i := 0; i < 1000; i++ {
+		m.Set([]byte{byte(i >> 8), byte(i)}, i)
+	}
+
+	probe := []byte{0x01, 0x02}
+	allocs := testing.AllocsPerRun(100, func() {
+		m.Get(probe)
+		m.Has(probe)
+		m.Seek(probe)
+		m.GetRank(probe)
+	})
+	if allocs != 0 {
+		t.Fatalf("lookups with a []byte probe should not allocate, got %v allocs", allocs)
+	}
+	if v, ok := m.Get(probe); !ok || v != 0x0102 {
+		t.Fatalf("Get(01 02): got %d (%v)", v, ok)
+	}
+}
+
+func TestBytesSkipList(t *testing.T) {
+	sl := NewBytesSkipList()
+	for _, k := range [][]byte{[]byte("b"), []byte("a"), []byte("ab"), {}} {
+		sl.Add(k)
+	}
+
+	var got [][]byte
+	sl.Range(func(k []byte) bool {
+		got = append(got, k)
+		return true
+	})
+	want := [][]byte{{}, []byte("a"), []byte("ab"), []byte("b")}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Fatalf("order mismatch at %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+	if CountPrefix(sl, []byte("a")) != 2 {
+		t.Fatal("prefix scans should work on byte skip lists")
+	}
+}
+
+func TestSkipMap_Iterator(t *testing.T) {
+	m := NewSkipMap[string, int]()
+	for i, k := range []string{"d", "b", "a", "c"} {
+		m.Set(k, i)
+	}
+
+	var keys []string
+	it := m.NewIterator()
+	for it.First(); it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if !stringsEqual(keys, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("iteration order: got %v", keys)
+	}
+
+	it.Seek("bb")
+	if !it.Valid() || it.Key() != "c" || it.Value() != 3 {
+		t.Fatalf("Seek(bb) should land on c=3")
+	}
+	it.Seek("e")
+	if it.Valid() {
+		t.Fatal("Seek past the last key should invalidate the iterator")
+	}
+	it.Next() // no-op when not valid
+}
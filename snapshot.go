@@ -0,0 +1,214 @@
+package skiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// ErrBadSnapshot is returned by Restore when a snapshot is truncated, fails
+// its checksum or describes an inconsistent list.
+var ErrBadSnapshot = errors.New("skiplist: bad snapshot")
+
+// snapshotMagic and snapshotVersion start every snapshot. The version is bumped
+// whenever the layout below changes.
+//
+//	magic    "SKSN"
+//	version  1 byte
+//	count    uvarint
+//	maxLevel uvarint
+//	levelCount[0..maxLevel]  uvarint each
+//	count × (level uvarint, length uvarint, element bytes)
+//	crc32    4 bytes, Castagnoli, little-endian, over everything before it
+const (
+	snapshotMagic   = "SKSN"
+	snapshotVersion = 1
+)
+
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// snapshotEntry is one node of a snapshot: its value and the level of its
+// tower.
+type snapshotEntry[T any] struct {
+	val T
+	lvl int
+}
+
+// Snapshot writes the exact structure of the list to w: every element with its
+// tower height, plus the level counts to check the restored list against.
+// Elements are encoded with the list's codec, as for MarshalBinary.
+//
+// Unlike MarshalBinary, a snapshot restores to a list with the same towers, so
+// it reproduces the search paths and spans of the original. This makes it
+// suitable for attaching to bug reports.
+func (sl *SkipList[T]) Snapshot(w io.Writer) error {
+	codec, err := sl.elementCodec()
+	if err != nil {
+		return err
+	}
+
+	buf := append([]byte(snapshotMagic), snapshotVersion)
+	buf = binary.AppendUvarint(buf, uint64(sl.length))
+	buf = binary.AppendUvarint(buf, uint64(sl.maxLevel))
+	for level := 0; level <= sl.maxLevel; level++ {
+		buf = binary.AppendUvarint(buf, uint64(sl.levelCount[level]))
+	}
+
+	for node := sl.firstNode(); node != nil; node = node.levels[0].next {
+		data, err := codec.Encode(node.val)
+		if err != nil {
+			return err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(node.levels)-1))
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(buf, snapshotTable))
+	_, err = w.Write(buf)
+	return err
+}
+
+// Restore replaces the list's contents with a snapshot written by Snapshot.
+// Each element is re-inserted at its recorded level with the same linking
+// logic as InsertAtLevel into a new list, whose spans, level counts and
+// maximum level are then checked against the snapshot.
+//
+// Restore returns an error wrapping ErrBadSnapshot for a corrupt snapshot. The
+// list is only replaced once the rebuilt list has passed every check, so a
+// failed Restore leaves it unchanged.
+func (sl *SkipList[T]) Restore(r io.Reader) error {
+	if err := sl.ensureComparator(); err != nil {
+		return err
+	}
+	codec, err := sl.elementCodec()
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(data) < len(snapshotMagic)+1+4 || !bytes.HasPrefix(data, []byte(snapshotMagic)) {
+		return fmt.Errorf("%w: missing header", ErrBadSnapshot)
+	}
+	if v := data[len(snapshotMagic)]; v != snapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrBadSnapshot, v)
+	}
+	body, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.Checksum(body, snapshotTable) != sum {
+		return fmt.Errorf("%w: checksum mismatch", ErrBadSnapshot)
+	}
+
+	d := byteDecoder{data: body[len(snapshotMagic)+1:]}
+	count := d.uvarint()
+	maxLevel := d.uvarint()
+	if d.err != nil || maxLevel > MaxLevelCap || count > len(d.data) {
+		return fmt.Errorf("%w: corrupt header", ErrBadSnapshot)
+	}
+	var levelCount [MaxLevelCap + 1]int
+	for level := 0; level <= maxLevel; level++ {
+		levelCount[level] = d.uvarint()
+	}
+
+	entries := make([]snapshotEntry[T], 0, count)
+	var towers [MaxLevelCap + 1]int
+	for i := 0; i < count; i++ {
+		lvl, elem := d.uvarint(), d.bytes()
+		if d.err != nil || lvl > maxLevel {
+			return fmt.Errorf("%w: corrupt node %d", ErrBadSnapshot, i)
+		}
+		val, err := codec.Decode(elem)
+		if err != nil {
+			return fmt.Errorf("%w: node %d: %v", ErrBadSnapshot, i, err)
+		}
+		if i > 0 && sl.comparator(entries[i-1].val, val) >= 0 {
+			return fmt.Errorf("%w: node %d is out of order", ErrBadSnapshot, i)
+		}
+		entries = append(entries, snapshotEntry[T]{val: val, lvl: lvl})
+		for level := 0; level <= lvl; level++ {
+			towers[level]++
+		}
+	}
+	if d.err != nil || len(d.data) != 0 {
+		return fmt.Errorf("%w: trailing bytes", ErrBadSnapshot)
+	}
+	if towers != levelCount || maxLevel > 0 && levelCount[maxLevel] == 0 {
+		return fmt.Errorf("%w: towers do not match the recorded level counts", ErrBadSnapshot)
+	}
+
+	// build into a fresh list sharing the configuration, so a snapshot that
+	// fails the final check leaves sl as it was
+	restored := &SkipList[T]{comparator: sl.comparator, codec: sl.codec}
+	restored.Clear()
+	restored.arena = sl.arena
+
+	// the values ascend, so the finger left by each link is already positioned
+	// on the next one and no search is needed
+	var f finger[T]
+	for i := range f.hierarchy {
+		f.hierarchy[i] = restored.head
+	}
+	for _, e := range entries {
+		restored.link(&f, e.val, e.lvl)
+	}
+
+	if err := restored.checkRestored(maxLevel, levelCount); err != nil {
+		// hand the nodes built so far back to the arena, if there is one
+		restored.Clear()
+		return err
+	}
+
+	sl.Clear()
+	sl.head, sl.maxLevel, sl.length, sl.levelCount = restored.head, restored.maxLevel, restored.length, restored.levelCount
+	return nil
+}
+
+// checkRestored verifies a restored list against its snapshot: the level
+// counts and maximum level must match, and the list must pass Validate.
+func (sl *SkipList[T]) checkRestored(maxLevel int, levelCount [MaxLevelCap + 1]int) error {
+	if sl.maxLevel != maxLevel || sl.levelCount != levelCount {
+		return fmt.Errorf("%w: restored levels differ from the snapshot", ErrBadSnapshot)
+	}
+	if err := sl.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+	}
+	return nil
+}
+
+// byteDecoder reads uvarints and length-prefixed byte strings from snapshots
+// and sorted tables, recording the first error instead of returning it from
+// every call.
+type byteDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *byteDecoder) uvarint() int {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 || x > math.MaxInt {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(x)
+}
+
+func (d *byteDecoder) bytes() []byte {
+	n := d.uvarint()
+	if d.err != nil || n > len(d.data) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
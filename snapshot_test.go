@@ -0,0 +1,177 @@
+package skiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Snapshot helpers
+// ------------------------------------------------------------
+
+// resealSnapshot replaces the checksum of a modified snapshot, so the test
+// reaches the structural checks behind it.
+func resealSnapshot(data []byte) []byte {
+	body := data[:len(data)-4]
+	return binary.LittleEndian.AppendUint32(body, crc32.Checksum(body, snapshotTable))
+}
+
+// ------------------------------------------------------------
+// Snapshot tests
+// ------------------------------------------------------------
+
+func TestSnapshot_RestoresExactShape(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for _, size := range []int{0, 1, 17, 1000} {
+		sl := NewSkipList[int]()
+		for sl.Len() < size {
+			sl.Add(rng.Intn(size * 10))
+		}
+		// deletes lower maxLevel and leave gaps in the towers
+		for i := 0; i < size/4; i++ {
+			sl.Delete(rng.Intn(size * 10))
+		}
+
+		var buf bytes.Buffer
+		if err := sl.Snapshot(&buf); err != nil {
+			t.Fatalf("Snapshot returned %v", err)
+		}
+		restored := NewSkipList[int]()
+		restored.Add(-1) // replaced by the snapshot
+		if err := restored.Restore(&buf); err != nil {
+			t.Fatalf("size %d: Restore returned %v", size, err)
+		}
+
+		if got, want := listShape(restored), listShape(sl); got != want {
+			t.Fatalf("size %d: restored shape differs:\nwant:\n%s\ngot:\n%s", size, want, got)
+		}
+		if restored.maxLevel != sl.maxLevel || restored.levelCount != sl.levelCount || restored.Len() != sl.Len() {
+			t.Fatalf("size %d: restored bookkeeping differs", size)
+		}
+	}
+}
+
+func TestSnapshot_CustomCodecAndArena(t *testing.T) {
+	sl := NewComparableSkipList[account]()
+	sl.UseCodec(accountCodec{})
+	sl.InsertAtLevel(account{id: "carol", balance: 3}, 2)
+	sl.InsertAtLevel(account{id: "alice", balance: 1}, 0)
+	sl.InsertAtLevel(account{id: "bob", balance: 2}, 4)
+
+	var buf bytes.Buffer
+	if err := sl.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot returned %v", err)
+	}
+
+	restored := NewComparableSkipList[account]()
+	restored.UseCodec(accountCodec{})
+	restored.UseArena(4)
+	if err := restored.Restore(&buf); err != nil {
+		t.Fatalf("Restore returned %v", err)
+	}
+	if node, _ := restored.SearchByValue(account{id: "bob"}); node.Value().balance != 2 || len(node.levels) != 5 {
+		t.Fatalf("bob should keep balance 2 and a tower of 5, got %+v with %d levels", node.Value(), len(node.levels))
+	}
+	if restored.maxLevel != 4 {
+		t.Fatalf("expected maxLevel 4, got %d", restored.maxLevel)
+	}
+}
+
+func TestRestore_RejectsCorruptSnapshots(t *testing.T) {
+	sl := NewSkipList[int]()
+	sl.InsertAtLevel(10, 1)
+	sl.InsertAtLevel(20, 0)
+	sl.InsertAtLevel(30, 2)
+
+	var buf bytes.Buffer
+	if err := sl.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot returned %v", err)
+	}
+	good := buf.Bytes()
+
+	flipped := bytes.Clone(good)
+	flipped[len(flipped)/2] ^= 0x40
+
+	wrongVersion := bytes.Clone(good)
+	wrongVersion[len(snapshotMagic)] = snapshotVersion + 1
+
+	// the level count of level 2 is the last byte of the header
+	wrongCount := bytes.Clone(good)
+	wrongCount[len(snapshotMagic)+1+2+2]++
+
+	// swap the first two nodes' values, 10 and 20
+	unsorted := bytes.Clone(good)
+	first := bytes.Index(unsorted, []byte{1, 1, 20})
+	second := bytes.Index(unsorted, []byte{0, 1, 40})
+	if first < 0 || second < 0 {
+		t.Fatalf("unexpected snapshot layout % x", good)
+	}
+	unsorted[first+2], unsorted[second+2] = 40, 20
+
+	cases := map[string][]byte{
+		"empty":         nil,
+		"truncated":     good[:len(good)-5],
+		"checksum":      flipped,
+		"version":       wrongVersion,
+		"level counts":  resealSnapshot(wrongCount),
+		"out of order":  resealSnapshot(unsorted),
+		"trailing byte": resealSnapshot(append(bytes.Clone(good[:len(good)-4]), 0, 0, 0, 0, 0)),
+	}
+
+	for name, data := range cases {
+		target := NewSkipList[int]()
+		target.Add(7)
+		if err := target.Restore(bytes.NewReader(data)); !errors.Is(err, ErrBadSnapshot) {
+			t.Fatalf("%s: expected ErrBadSnapshot, got %v", name, err)
+		}
+		if target.Len() != 1 || !target.Contains(7) {
+			t.Fatalf("%s: a rejected snapshot should leave the list unchanged", name)
+		}
+	}
+}
+
+func TestRestore_FailureKeepsNonEmptyList(t *testing.T) {
+	source := NewSkipList[int]()
+	for i := 1; i <= 50; i++ {
+		source.Add(i * 100)
+	}
+	var buf bytes.Buffer
+	if err := source.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot returned %v", err)
+	}
+	flipped := bytes.Clone(buf.Bytes())
+	flipped[len(flipped)/2] ^= 0x01
+	trailing := resealSnapshot(append(bytes.Clone(buf.Bytes()[:buf.Len()-4]), 0, 0, 0, 0, 0))
+
+	target := NewSkipList[int]()
+	target.UseArena(8)
+	for i := 0; i < 30; i++ {
+		target.Add(i)
+	}
+	target.Delete(13)
+	before := listShape(target)
+
+	for _, data := range [][]byte{flipped, trailing} {
+		if err := target.Restore(bytes.NewReader(data)); !errors.Is(err, ErrBadSnapshot) {
+			t.Fatalf("expected ErrBadSnapshot, got %v", err)
+		}
+		if after := listShape(target); after != before {
+			t.Fatalf("a failed Restore changed the list:\n%s\nwant:\n%s", after, before)
+		}
+		if err := target.Validate(); err != nil {
+			t.Fatalf("a failed Restore left an invalid list: %v", err)
+		}
+	}
+
+	// a good snapshot still replaces the contents, reusing the list's arena
+	if err := target.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Restore returned %v", err)
+	}
+	if listShape(target) != listShape(source) || target.arena == nil {
+		t.Fatal("restored list should match the source and keep its arena")
+	}
+}
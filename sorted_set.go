@@ -0,0 +1,368 @@
+package skiplist
+
+import (
+	"cmp"
+	"errors"
+)
+
+// ErrIncompatibleFlags is returned by ZAdd when mutually exclusive flags are combined.
+var ErrIncompatibleFlags = errors.New("skiplist: GT, LT, NX and XX flags are not compatible")
+
+// ZAddFlag modifies the behavior of SortedSet.ZAdd. Flags can be combined with |.
+type ZAddFlag int
+
+const (
+	// ZAddNX only adds new members and never updates existing ones.
+	ZAddNX ZAddFlag = 1 << iota
+	// ZAddXX only updates existing members and never adds new ones.
+	ZAddXX
+	// ZAddGT only updates existing members if the new score is greater.
+	ZAddGT
+	// ZAddLT only updates existing members if the new score is less.
+	ZAddLT
+	// ZAddCH makes ZAdd count changed members in addition to added ones.
+	ZAddCH
+)
+
+// Z is a sorted set member together with its score.
+type Z[M comparable, S cmp.Ordered] struct {
+	Member M
+	Score  S
+}
+
+// ScoreBound is one end of a score range. A bound is inclusive unless
+// Exclusive is set; an Unbounded bound stands for -inf or +inf.
+type ScoreBound[S cmp.Ordered] struct {
+	Value     S
+	Exclusive bool
+	Unbounded bool
+}
+
+// ScoreInclusive returns a bound that includes v.
+func ScoreInclusive[S cmp.Ordered](v S) ScoreBound[S] {
+	return ScoreBound[S]{Value: v}
+}
+
+// ScoreExclusive returns a bound that excludes v.
+func ScoreExclusive[S cmp.Ordered](v S) ScoreBound[S] {
+	return ScoreBound[S]{Value: v, Exclusive: true}
+}
+
+// ScoreUnbounded returns an open bound: -inf as a minimum, +inf as a maximum.
+func ScoreUnbounded[S cmp.Ordered]() ScoreBound[S] {
+	return ScoreBound[S]{Unbounded: true}
+}
+
+// SortedSet is a Redis-style sorted set (ZSET). Like Redis, it pairs a
+// member→score map for O(1) score lookups with a skip list ordered by
+// (score, member) whose rank spans answer positional queries in O(log n).
+//
+// Ranks and rank ranges follow Redis conventions: they are 0-based and negative
+// indexes count from the end, so -1 is the member with the highest score.
+type SortedSet[M comparable, S cmp.Ordered] struct {
+	scores map[M]S
+	list   *SkipList[Z[M, S]]
+}
+
+// NewSortedSet creates a sorted set whose members with equal scores are ordered
+// by member, matching Redis' lexicographic tie-break for string members.
+func NewSortedSet[M cmp.Ordered, S cmp.Ordered]() *SortedSet[M, S] {
+	return NewSortedSetFunc[M, S](cmp.Compare[M])
+}
+
+// NewSortedSetFunc creates a sorted set whose members with equal scores are
+// ordered by memberCmp.
+func NewSortedSetFunc[M comparable, S cmp.Ordered](memberCmp Comparator[M]) *SortedSet[M, S] {
+	return &SortedSet[M, S]{
+		scores: make(map[M]S),
+		list: newSkipList(func(a, b Z[M, S]) int {
+			if c := cmp.Compare(a.Score, b.Score); c != 0 {
+				return c
+			}
+			return memberCmp(a.Member, b.Member)
+		}),
+	}
+}
+
+// ZAdd adds members or updates their scores according to flags. It returns the
+// number of members added, plus the number of members whose score changed when
+// ZAddCH is set.
+//
+// Time Complexity: O(log n) average per member
+func (z *SortedSet[M, S]) ZAdd(flags ZAddFlag, members ...Z[M, S]) (int, error) {
+	nx, xx := flags&ZAddNX != 0, flags&ZAddXX != 0
+	gt, lt := flags&ZAddGT != 0, flags&ZAddLT != 0
+	if (nx && xx) || (gt && lt) || (nx && (gt || lt)) {
+		return 0, ErrIncompatibleFlags
+	}
+
+	added, changed := 0, 0
+	for _, m := range members {
+		old, exists := z.scores[m.Member]
+		switch {
+		case exists && nx, !exists && xx:
+			continue
+		case !exists:
+			z.scores[m.Member] = m.Score
+			z.list.Add(m)
+			added++
+			continue
+		case gt && cmp.Compare(m.Score, old) <= 0,
+			lt && cmp.Compare(m.Score, old) >= 0,
+			cmp.Compare(m.Score, old) == 0:
+			continue
+		}
+
+		z.setScore(m.Member, old, m.Score)
+		changed++
+	}
+
+	if flags&ZAddCH != 0 {
+		return added + changed, nil
+	}
+	return added, nil
+}
+
+// ZIncrBy adds delta to the score of member, adding the member with score delta
+// if it doesn't exist, and returns the new score.
+func (z *SortedSet[M, S]) ZIncrBy(member M, delta S) S {
+	old, exists := z.scores[member]
+	if !exists {
+		z.scores[member] = delta
+		z.list.Add(Z[M, S]{Member: member, Score: delta})
+		return delta
+	}
+
+	score := old + delta
+	z.setScore(member, old, score)
+	return score
+}
+
+func (z *SortedSet[M, S]) setScore(member M, old, score S) {
+	z.list.Delete(Z[M, S]{Member: member, Score: old})
+	z.list.Add(Z[M, S]{Member: member, Score: score})
+	z.scores[member] = score
+}
+
+// ZScore returns the score of member.
+func (z *SortedSet[M, S]) ZScore(member M) (S, bool) {
+	score, exists := z.scores[member]
+	return score, exists
+}
+
+// ZCard returns the number of members in the set.
+func (z *SortedSet[M, S]) ZCard() int {
+	return z.list.Len()
+}
+
+// ZRank returns the 0-based rank of member, ordered from the lowest score.
+//
+// Time Complexity: O(log n) average
+func (z *SortedSet[M, S]) ZRank(member M) (int, bool) {
+	score, exists := z.scores[member]
+	if !exists {
+		return -1, false
+	}
+
+	rank, _ := z.list.GetRank(Z[M, S]{Member: member, Score: score})
+	return rank - 1, true
+}
+
+// ZRevRank returns the 0-based rank of member, ordered from the highest score.
+//
+// Time Complexity: O(log n) average
+func (z *SortedSet[M, S]) ZRevRank(member M) (int, bool) {
+	rank, exists := z.ZRank(member)
+	if !exists {
+		return -1, false
+	}
+	return z.list.Len() - 1 - rank, true
+}
+
+// ZRange returns the members with ranks start through stop inclusive, ordered
+// from the lowest score.
+//
+// Time Complexity: O(log n + k) average, where k is the number of results
+func (z *SortedSet[M, S]) ZRange(start, stop int) []Z[M, S] {
+	start, stop, ok := z.normalizeRange(start, stop)
+	if !ok {
+		return nil
+	}
+
+	out := make([]Z[M, S], 0, stop-start+1)
+	node, _ := z.list.SearchByRank(start + 1)
+	for i := start; i <= stop; i++ {
+		out = append(out, node.val)
+		node = node.levels[0].next
+	}
+	return out
+}
+
+// ZRevRange returns the members with ranks start through stop inclusive,
+// ordered from the highest score.
+//
+// Time Complexity: O(log n + k) average, where k is the number of results
+func (z *SortedSet[M, S]) ZRevRange(start, stop int) []Z[M, S] {
+	start, stop, ok := z.normalizeRange(start, stop)
+	if !ok {
+		return nil
+	}
+
+	n := z.list.Len()
+	out := z.ZRange(n-1-stop, n-1-start)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// ZRangeByScore returns the members with scores between min and max, ordered
+// from the lowest score. After skipping offset matching members, at most count
+// members are returned; a negative count means no limit.
+//
+// Time Complexity: O(log n + offset + k) average, where k is the number of results
+func (z *SortedSet[M, S]) ZRangeByScore(min, max ScoreBound[S], offset, count int) []Z[M, S] {
+	node, _ := z.list.seek(belowMin[M](min))
+	for ; node != nil && offset > 0; offset-- {
+		node = node.levels[0].next
+	}
+
+	var out []Z[M, S]
+	inRange := withinMax[M](max)
+	for ; node != nil && count != 0 && inRange(node.val); node = node.levels[0].next {
+		out = append(out, node.val)
+		count--
+	}
+	return out
+}
+
+// ZCount returns the number of members with scores between min and max.
+//
+// Time Complexity: O(log n) average
+func (z *SortedSet[M, S]) ZCount(min, max ScoreBound[S]) int {
+	_, first := z.list.seek(belowMin[M](min))
+	_, end := z.list.seek(withinMax[M](max))
+	if end < first {
+		return 0
+	}
+	return end - first
+}
+
+// ZRem removes members and returns how many of them existed.
+func (z *SortedSet[M, S]) ZRem(members ...M) int {
+	removed := 0
+	for _, member := range members {
+		score, exists := z.scores[member]
+		if !exists {
+			continue
+		}
+		z.list.Delete(Z[M, S]{Member: member, Score: score})
+		delete(z.scores, member)
+		removed++
+	}
+	return removed
+}
+
+// ZRemRangeByRank removes the members with ranks start through stop inclusive
+// and returns how many were removed.
+//
+// Time Complexity: O(log n + k) average, where k is the number of removed members
+func (z *SortedSet[M, S]) ZRemRangeByRank(start, stop int) int {
+	removed := z.ZRange(start, stop)
+	if len(removed) == 0 {
+		return 0
+	}
+
+	first, last := removed[0], removed[len(removed)-1]
+	z.list.deleteBetween(
+		func(e Z[M, S]) bool { return z.list.comparator(e, first) < 0 },
+		func(e Z[M, S]) bool { return z.list.comparator(e, last) <= 0 },
+	)
+	for _, e := range removed {
+		delete(z.scores, e.Member)
+	}
+	return len(removed)
+}
+
+// ZRemRangeByScore removes the members with scores between min and max and
+// returns how many were removed.
+//
+// Time Complexity: O(log n + k) average, where k is the number of removed members
+func (z *SortedSet[M, S]) ZRemRangeByScore(min, max ScoreBound[S]) int {
+	for _, e := range z.ZRangeByScore(min, max, 0, -1) {
+		delete(z.scores, e.Member)
+	}
+	return z.list.deleteBetween(belowMin[M](min), withinMax[M](max))
+}
+
+// ZPopMin removes and returns up to count members with the lowest scores,
+// lowest first.
+func (z *SortedSet[M, S]) ZPopMin(count int) []Z[M, S] {
+	if count <= 0 {
+		return nil
+	}
+	out := z.ZRange(0, count-1)
+	z.ZRemRangeByRank(0, count-1)
+	return out
+}
+
+// ZPopMax removes and returns up to count members with the highest scores,
+// highest first.
+func (z *SortedSet[M, S]) ZPopMax(count int) []Z[M, S] {
+	if count <= 0 {
+		return nil
+	}
+	out := z.ZRevRange(0, count-1)
+	z.ZRemRangeByRank(-count, -1)
+	return out
+}
+
+// normalizeRange converts Redis-style rank indexes into a valid inclusive
+// 0-based range, reporting false if the range is empty.
+func (z *SortedSet[M, S]) normalizeRange(start, stop int) (int, int, bool) {
+	n := z.list.Len()
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop {
+		return 0, 0, false
+	}
+	return start, stop, true
+}
+
+// belowMin reports whether an entry sorts before the range starting at min.
+func belowMin[M comparable, S cmp.Ordered](min ScoreBound[S]) func(Z[M, S]) bool {
+	return func(e Z[M, S]) bool {
+		switch {
+		case min.Unbounded:
+			return false
+		case min.Exclusive:
+			return cmp.Compare(e.Score, min.Value) <= 0
+		default:
+			return cmp.Compare(e.Score, min.Value) < 0
+		}
+	}
+}
+
+// withinMax reports whether an entry sorts before the end of the range ending at max.
+func withinMax[M comparable, S cmp.Ordered](max ScoreBound[S]) func(Z[M, S]) bool {
+	return func(e Z[M, S]) bool {
+		switch {
+		case max.Unbounded:
+			return true
+		case max.Exclusive:
+			return cmp.Compare(e.Score, max.Value) < 0
+		default:
+			return cmp.Compare(e.Score, max.Value) <= 0
+		}
+	}
+}
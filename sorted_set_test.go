@@ -0,0 +1,227 @@
+package skiplist
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Sorted set helpers
+// ------------------------------------------------------------
+
+type zset = SortedSet[string, float64]
+type zpair = Z[string, float64]
+
+func newTestZSet(t *testing.T, pairs ...zpair) *zset {
+	t.Helper()
+	z := NewSortedSet[string, float64]()
+	if _, err := z.ZAdd(0, pairs...); err != nil {
+		t.Fatalf("ZAdd failed: %v", err)
+	}
+	return z
+}
+
+func members(zs []zpair) []string {
+	out := make([]string, len(zs))
+	for i, e := range zs {
+		out[i] = e.Member
+	}
+	return out
+}
+
+func assertMembers(t *testing.T, what string, got []zpair, want ...string) {
+	t.Helper()
+	g := members(got)
+	if len(g) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, g, want)
+	}
+	for i := range g {
+		if g[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", what, g, want)
+		}
+	}
+}
+
+// assertZSetRanks checks that every member's rank agrees with a sorted copy of
+// the expected contents, which exercises the spans left behind by range deletes.
+func assertZSetRanks(t *testing.T, z *zset, want map[string]float64) {
+	t.Helper()
+	sorted := make([]zpair, 0, len(want))
+	for m, s := range want {
+		sorted = append(sorted, zpair{Member: m, Score: s})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Score != sorted[j].Score {
+			return sorted[i].Score < sorted[j].Score
+		}
+		return sorted[i].Member < sorted[j].Member
+	})
+
+	if z.ZCard() != len(sorted) {
+		t.Fatalf("ZCard: got %d, want %d", z.ZCard(), len(sorted))
+	}
+	for i, e := range sorted {
+		if rank, ok := z.ZRank(e.Member); !ok || rank != i {
+			t.Fatalf("ZRank(%s): got %d (%v), want %d", e.Member, rank, ok, i)
+		}
+		if node, ok := z.list.SearchByRank(i + 1); !ok || node.val != e {
+			t.Fatalf("rank %d: got %v, want %v", i+1, node, e)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// Sorted set tests
+// ------------------------------------------------------------
+
+func TestZAdd_Flags(t *testing.T) {
+	z := newTestZSet(t, zpair{"a", 1}, zpair{"b", 2})
+
+	if n, _ := z.ZAdd(ZAddNX, zpair{"a", 10}, zpair{"c", 3}); n != 1 {
+		t.Fatalf("NX should only add c, got %d", n)
+	}
+	if s, _ := z.ZScore("a"); s != 1 {
+		t.Fatalf("NX must not update a, score %v", s)
+	}
+
+	if n, _ := z.ZAdd(ZAddXX|ZAddCH, zpair{"a", 5}, zpair{"d", 4}); n != 1 {
+		t.Fatalf("XX CH should change only a, got %d", n)
+	}
+	if z.ZCard() != 3 {
+		t.Fatalf("XX must not add d, card %d", z.ZCard())
+	}
+
+	if n, _ := z.ZAdd(ZAddGT|ZAddCH, zpair{"a", 4}, zpair{"b", 7}); n != 1 {
+		t.Fatalf("GT should only raise b, got %d", n)
+	}
+	if n, _ := z.ZAdd(ZAddLT|ZAddCH, zpair{"a", 9}, zpair{"c", 0}); n != 1 {
+		t.Fatalf("LT should only lower c, got %d", n)
+	}
+	assertMembers(t, "order", z.ZRange(0, -1), "c", "a", "b")
+
+	for _, flags := range []ZAddFlag{ZAddNX | ZAddXX, ZAddGT | ZAddLT, ZAddNX | ZAddGT} {
+		if _, err := z.ZAdd(flags, zpair{"x", 1}); !errors.Is(err, ErrIncompatibleFlags) {
+			t.Fatalf("flags %b: expected ErrIncompatibleFlags, got %v", flags, err)
+		}
+	}
+}
+
+func TestZIncrBy(t *testing.T) {
+	z := newTestZSet(t, zpair{"a", 1}, zpair{"b", 2})
+
+	if s := z.ZIncrBy("a", 5); s != 6 {
+		t.Fatalf("ZIncrBy should return 6, got %v", s)
+	}
+	if s := z.ZIncrBy("new", 1.5); s != 1.5 {
+		t.Fatalf("ZIncrBy on missing member should return delta, got %v", s)
+	}
+	assertMembers(t, "order", z.ZRange(0, -1), "new", "b", "a")
+}
+
+func TestZRankAndRange(t *testing.T) {
+	z := newTestZSet(t, zpair{"a", 1}, zpair{"b", 2}, zpair{"c", 2}, zpair{"d", 3})
+
+	if r, _ := z.ZRank("c"); r != 2 {
+		t.Fatalf("ZRank(c) = %d, want 2", r)
+	}
+	if r, _ := z.ZRevRank("c"); r != 1 {
+		t.Fatalf("ZRevRank(c) = %d, want 1", r)
+	}
+	if _, ok := z.ZRank("missing"); ok {
+		t.Fatal("ZRank of missing member should report false")
+	}
+
+	assertMembers(t, "ZRange(1, 2)", z.ZRange(1, 2), "b", "c")
+	assertMembers(t, "ZRange(-2, -1)", z.ZRange(-2, -1), "c", "d")
+	assertMembers(t, "ZRange(0, 100)", z.ZRange(0, 100), "a", "b", "c", "d")
+	assertMembers(t, "ZRange(3, 1)", z.ZRange(3, 1))
+	assertMembers(t, "ZRevRange(0, 1)", z.ZRevRange(0, 1), "d", "c")
+}
+
+func TestZRangeByScore(t *testing.T) {
+	z := newTestZSet(t, zpair{"a", 1}, zpair{"b", 2}, zpair{"c", 3}, zpair{"d", 4}, zpair{"e", 5})
+
+	inf := ScoreUnbounded[float64]()
+	assertMembers(t, "[2, 4]", z.ZRangeByScore(ScoreInclusive(2.0), ScoreInclusive(4.0), 0, -1), "b", "c", "d")
+	assertMembers(t, "(2, 4)", z.ZRangeByScore(ScoreExclusive(2.0), ScoreExclusive(4.0), 0, -1), "c")
+	assertMembers(t, "-inf..+inf", z.ZRangeByScore(inf, inf, 0, -1), "a", "b", "c", "d", "e")
+	assertMembers(t, "LIMIT 1 2", z.ZRangeByScore(inf, inf, 1, 2), "b", "c")
+	assertMembers(t, "empty", z.ZRangeByScore(ScoreExclusive(5.0), inf, 0, -1))
+
+	if n := z.ZCount(ScoreExclusive(1.0), ScoreInclusive(4.0)); n != 3 {
+		t.Fatalf("ZCount((1, 4]) = %d, want 3", n)
+	}
+	if n := z.ZCount(ScoreInclusive(4.0), ScoreInclusive(2.0)); n != 0 {
+		t.Fatalf("ZCount with min > max should be 0, got %d", n)
+	}
+}
+
+func TestZRemVariants(t *testing.T) {
+	z := newTestZSet(t, zpair{"a", 1}, zpair{"b", 2}, zpair{"c", 3}, zpair{"d", 4}, zpair{"e", 5}, zpair{"f", 6})
+
+	if n := z.ZRem("a", "missing"); n != 1 {
+		t.Fatalf("ZRem should remove 1, got %d", n)
+	}
+	if n := z.ZRemRangeByRank(1, 2); n != 2 {
+		t.Fatalf("ZRemRangeByRank should remove 2, got %d", n)
+	}
+	assertZSetRanks(t, z, map[string]float64{"b": 2, "e": 5, "f": 6})
+
+	if n := z.ZRemRangeByScore(ScoreExclusive(2.0), ScoreInclusive(5.0)); n != 1 {
+		t.Fatalf("ZRemRangeByScore should remove 1, got %d", n)
+	}
+	assertZSetRanks(t, z, map[string]float64{"b": 2, "f": 6})
+}
+
+func TestZPop(t *testing.T) {
+	z := newTestZSet(t, zpair{"a", 1}, zpair{"b", 2}, zpair{"c", 3}, zpair{"d", 4})
+
+	assertMembers(t, "ZPopMin(1)", z.ZPopMin(1), "a")
+	assertMembers(t, "ZPopMax(2)", z.ZPopMax(2), "d", "c")
+	assertMembers(t, "ZPopMax(5)", z.ZPopMax(5), "b")
+	assertMembers(t, "ZPopMin on empty", z.ZPopMin(1))
+
+	if _, ok := z.ZScore("b"); ok {
+		t.Fatal("popped members must be removed from the score map")
+	}
+}
+
+func TestSortedSet_RandomRangeDeletes(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	z := NewSortedSet[string, float64]()
+	model := map[string]float64{}
+
+	for step := 0; step < 500; step++ {
+		switch rng.Intn(4) {
+		case 0:
+			lo := float64(rng.Intn(100))
+			hi := lo + float64(rng.Intn(20))
+			z.ZRemRangeByScore(ScoreInclusive(lo), ScoreExclusive(hi))
+			for m, s := range model {
+				if s >= lo && s < hi {
+					delete(model, m)
+				}
+			}
+		case 1:
+			if len(model) > 0 {
+				start := rng.Intn(len(model))
+				removed := z.ZRange(start, start+rng.Intn(5))
+				z.ZRemRangeByRank(start, start+len(removed)-1)
+				for _, e := range removed {
+					delete(model, e.Member)
+				}
+			}
+		default:
+			for i := 0; i < 5; i++ {
+				m := fmt.Sprintf("m%d", rng.Intn(200))
+				s := float64(rng.Intn(100))
+				z.ZAdd(0, zpair{m, s})
+				model[m] = s
+			}
+		}
+		assertZSetRanks(t, z, model)
+	}
+}
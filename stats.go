@@ -0,0 +1,153 @@
+package skiplist
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// Stats describes the shape of a skip list, for checking whether Probability
+// and MaxLevelCap suit the sizes it holds. With p = 0.5 and 17 levels, a list
+// of more than about 2^16 = 65536 elements starts to fill the top level, and
+// searches degrade towards a linear scan of it.
+type Stats struct {
+	// Len is the number of elements.
+	Len int
+	// MaxLevel is the highest level holding a node.
+	MaxLevel int
+	// LevelCounts holds, for every level up to MaxLevel, the number of nodes
+	// whose tower reaches it.
+	LevelCounts []int
+	// AvgHeight and MaxHeight describe the tower heights, in levels. With
+	// probability p the expected average is 1/(1-p).
+	AvgHeight float64
+	MaxHeight int
+	// AtCap is the number of towers that reach MaxLevelCap and were capped.
+	AtCap int
+	// ExpectedPath is Pugh's bound on the expected search path length for Len
+	// elements, with levels capped at MaxLevelCap. AvgPath and MaxPath are the
+	// actual lengths of the searches for every element. A path length counts
+	// the links followed plus the levels descended.
+	ExpectedPath float64
+	AvgPath      float64
+	MaxPath      int
+	// Bytes estimates the memory held by the nodes and towers, excluding
+	// memory referenced by the elements themselves.
+	Bytes int
+}
+
+// Stats walks the list and reports its shape.
+//
+// Time Complexity: O(n log n), since it measures the search path of every
+// element
+func (sl *SkipList[T]) Stats() Stats {
+	st := Stats{Len: sl.length, MaxLevel: sl.maxLevel, Bytes: int(unsafe.Sizeof(*sl))}
+	st.LevelCounts = append([]int(nil), sl.levelCount[:sl.maxLevel+1]...)
+	st.AtCap = sl.levelCount[MaxLevelCap]
+	st.ExpectedPath = expectedPath(sl.length)
+	if sl.head == nil {
+		return st
+	}
+	st.Bytes += nodeFootprint[T](MaxLevelCap+1, false)
+
+	totalHeight, totalPath := 0, 0
+	for node := sl.head.levels[0].next; node != nil; node = node.levels[0].next {
+		height := len(node.levels)
+		totalHeight += height
+		st.MaxHeight = max(st.MaxHeight, height)
+		st.Bytes += nodeFootprint[T](height, sl.arena != nil)
+
+		path := sl.searchPath(node)
+		totalPath += path
+		st.MaxPath = max(st.MaxPath, path)
+	}
+	if sl.length > 0 {
+		st.AvgHeight = float64(totalHeight) / float64(sl.length)
+		st.AvgPath = float64(totalPath) / float64(sl.length)
+	}
+	return st
+}
+
+// searchPath returns the length of the path SearchByValue takes to target: the
+// links it follows plus the levels it descends before reaching target.
+func (sl *SkipList[T]) searchPath(target *Node[T]) int {
+	curr, steps := sl.head, 0
+	for lvl := sl.maxLevel; lvl >= 0; lvl-- {
+		for curr.levels[lvl].next != nil && sl.comparator(curr.levels[lvl].next.val, target.val) <= 0 {
+			curr = curr.levels[lvl].next
+			steps++
+		}
+		if curr == target {
+			return steps
+		}
+		steps++
+	}
+	return steps
+}
+
+// expectedPath is Pugh's bound L/p + 1/(1-p) on the expected search path for n
+// elements, where L = log_{1/p} n is the level expected to hold about one node.
+// When L exceeds MaxLevelCap, the top level instead holds about n·p^MaxLevelCap
+// nodes, all of which a search may have to pass.
+func expectedPath(n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	p := float64(Probability)
+	l := min(math.Log(float64(n))/math.Log(1/p), MaxLevelCap)
+	return l/p + float64(n)*math.Pow(p, l)/(1-p)
+}
+
+// nodeFootprint estimates the bytes held by a node with a tower of height
+// levels: arena nodes use exactly what they need, and heap nodes use the block
+// class NewNode allocates.
+func nodeFootprint[T any](height int, arena bool) int {
+	nodeSize := int(unsafe.Sizeof(Node[T]{}))
+	levelSize := int(unsafe.Sizeof(level[T]{}))
+	if arena {
+		return nodeSize + height*levelSize
+	}
+	switch {
+	case height <= 4:
+	case height <= 8:
+		height = 8
+	case height <= MaxLevelCap+1:
+		height = MaxLevelCap + 1
+	}
+	return nodeSize + height*levelSize
+}
+
+// String formats the statistics as a small report for capacity planning.
+func (st Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elements:      %d\n", st.Len)
+	fmt.Fprintf(&b, "levels:        %d of %d (p = %g)\n", st.MaxLevel+1, MaxLevelCap+1, Probability)
+	fmt.Fprintf(&b, "tower height:  avg %.2f, max %d, %d at the cap\n", st.AvgHeight, st.MaxHeight, st.AtCap)
+	fmt.Fprintf(&b, "search path:   avg %.2f, max %d, expected %.2f\n", st.AvgPath, st.MaxPath, st.ExpectedPath)
+	fmt.Fprintf(&b, "memory:        %s\n", formatBytes(st.Bytes))
+	b.WriteString("level histogram:\n")
+
+	widest := 1
+	for _, n := range st.LevelCounts {
+		widest = max(widest, n)
+	}
+	for lvl := len(st.LevelCounts) - 1; lvl >= 0; lvl-- {
+		n := st.LevelCounts[lvl]
+		bar := strings.Repeat("#", (n*40+widest-1)/widest)
+		fmt.Fprintf(&b, "  L%-2d %8d %s\n", lvl, n, bar)
+	}
+	return b.String()
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,96 @@
+package skiplist
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Stats tests
+// ------------------------------------------------------------
+
+func TestStats_DeterministicShape(t *testing.T) {
+	// 10 (level 1), 20 (level 0), 30 (level 2), 40 (level 0)
+	s := NewSkipList[int]()
+	s.InsertAtLevel(10, 1)
+	s.InsertAtLevel(20, 0)
+	s.InsertAtLevel(30, 2)
+	s.InsertAtLevel(40, 0)
+
+	st := s.Stats()
+	if st.Len != 4 || st.MaxLevel != 2 || !slicesEqual(st.LevelCounts, []int{4, 2, 1}) {
+		t.Fatalf("unexpected levels: len %d, max level %d, counts %v", st.Len, st.MaxLevel, st.LevelCounts)
+	}
+	if st.AvgHeight != 1.75 || st.MaxHeight != 3 || st.AtCap != 0 {
+		t.Fatalf("unexpected heights: avg %v, max %d, at cap %d", st.AvgHeight, st.MaxHeight, st.AtCap)
+	}
+	// paths: 10 takes 2 steps, 20 takes 4, 30 takes 1 and 40 takes 4
+	if st.AvgPath != 2.75 || st.MaxPath != 4 {
+		t.Fatalf("unexpected paths: avg %v, max %d", st.AvgPath, st.MaxPath)
+	}
+	if st.Bytes <= 0 {
+		t.Fatalf("expected a positive footprint, got %d", st.Bytes)
+	}
+
+	s.InsertAtLevel(50, MaxLevelCap)
+	if st := s.Stats(); st.AtCap != 1 || st.MaxHeight != MaxLevelCap+1 {
+		t.Fatalf("expected one tower at the cap, got %d with max height %d", st.AtCap, st.MaxHeight)
+	}
+}
+
+func TestStats_RandomListsStayNearExpectations(t *testing.T) {
+	s := NewSkipList[int]()
+	for i := 0; i < 20000; i++ {
+		s.Add(i)
+	}
+	st := s.Stats()
+	if st.AvgHeight < 1.8 || st.AvgHeight > 2.2 {
+		t.Fatalf("expected an average height near 2, got %.2f", st.AvgHeight)
+	}
+	if st.AvgPath > st.ExpectedPath*1.25 {
+		t.Fatalf("average path %.2f is far above the expected %.2f", st.AvgPath, st.ExpectedPath)
+	}
+
+	arena := NewSkipList[int]()
+	arena.UseArena(0)
+	for i := 0; i < 20000; i++ {
+		arena.Add(i)
+	}
+	if arena.Stats().Bytes >= st.Bytes {
+		t.Fatal("arena nodes should need fewer bytes than block-allocated nodes")
+	}
+}
+
+func TestStats_ExpectedPathGrowsLinearlyPastTheCap(t *testing.T) {
+	// at p = 0.5 the bound is 2·log2(n) + 2 up to the cap, after which the top
+	// level holds n/2^16 nodes and the bound grows with n
+	for n, want := range map[int]float64{1 << 10: 22, 1 << 16: 34, 1 << 20: 64} {
+		if got := expectedPath(n); math.Abs(got-want) > 1e-9 {
+			t.Fatalf("expectedPath(%d) = %.4f, want %.0f", n, got, want)
+		}
+	}
+	var zero SkipList[int]
+	if st := zero.Stats(); st.Len != 0 || st.ExpectedPath != 0 || st.AvgPath != 0 {
+		t.Fatalf("unexpected stats for a zero list: %+v", st)
+	}
+}
+
+func TestStats_String(t *testing.T) {
+	s := NewSkipList[int]()
+	s.InsertAtLevel(10, 1)
+	s.InsertAtLevel(20, 0)
+
+	report := s.Stats().String()
+	for _, want := range []string{
+		"elements:      2\n",
+		"levels:        2 of 17 (p = 0.5)\n",
+		"tower height:  avg 1.50, max 2, 0 at the cap\n",
+		"  L1         1 ####################\n",
+		"  L0         2 ########################################\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("report is missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package skiplist
+
+import (
+	"cmp"
+	"sync"
+)
+
+// ReadOnly is the read-only side of a skip list. *SkipList satisfies it, and
+// SyncSkipList.View hands one out for reads that must see a single consistent
+// state. View passes a wrapper rather than the list itself, so the value it
+// hands out cannot be converted back to a *SkipList and written to.
+type ReadOnly[T any] interface {
+	Len() int
+	IsEmpty() bool
+	Contains(val T) bool
+	SearchByValue(val T) (*Node[T], bool)
+	SearchByRank(rank int) (*Node[T], bool)
+	SearchByRevRank(rank int) (*Node[T], bool)
+	GetLowerBound(val T) (*Node[T], bool)
+	GetRank(item T) (int, bool)
+	GetRevRank(item T) (int, bool)
+	Range(fn func(val T) bool)
+}
+
+// SyncSkipList is a skip list that is safe for concurrent use. Reads share an
+// RWMutex read lock and run in parallel; writes hold it exclusively.
+//
+// Lookups return values rather than nodes, since a node may be modified or
+// recycled as soon as the lock is released. Use View to work with nodes or to
+// combine several reads into one consistent snapshot.
+type SyncSkipList[T any] struct {
+	mu   sync.RWMutex
+	list *SkipList[T]
+}
+
+// NewSyncSkipList creates a concurrency-safe skip list for ordered types.
+func NewSyncSkipList[T cmp.Ordered]() *SyncSkipList[T] {
+	return &SyncSkipList[T]{list: NewSkipList[T]()}
+}
+
+// NewComparableSyncSkipList creates a concurrency-safe skip list for types
+// that implement the Comparable interface.
+func NewComparableSyncSkipList[T Comparable[T]]() *SyncSkipList[T] {
+	return &SyncSkipList[T]{list: NewComparableSkipList[T]()}
+}
+
+// Add inserts val into the list. Adding an existing value is a no-op.
+func (s *SyncSkipList[T]) Add(val T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.list.Add(val)
+}
+
+// InsertAtLevel inserts val with a tower of the given level. It exists for
+// deterministic tests, like SkipList.InsertAtLevel.
+func (s *SyncSkipList[T]) InsertAtLevel(val T, lvl int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.list.InsertAtLevel(val, lvl)
+}
+
+// Delete removes val from the list if it exists.
+func (s *SyncSkipList[T]) Delete(val T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.list.Delete(val)
+}
+
+// Clear removes all elements from the list.
+func (s *SyncSkipList[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.list.Clear()
+}
+
+// Apply applies the batch under the write lock, so readers see either none or
+// all of it. The batch's validation hook runs under the lock as well and must
+// not call methods on s.
+func (s *SyncSkipList[T]) Apply(b *Batch[T]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list.Apply(b)
+}
+
+// GetOrAdd returns the stored element equal to val if there is one. Otherwise
+// it adds val and returns it. loaded reports whether the element was already
+// present.
+func (s *SyncSkipList[T]) GetOrAdd(val T) (actual T, loaded bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if node, found := s.list.SearchByValue(val); found {
+		return node.val, true
+	}
+	s.list.Add(val)
+	return val, false
+}
+
+// CompareAndDelete deletes the stored element equal to val, but only if match
+// reports true for it. It returns whether an element was deleted.
+//
+// Because elements are compared with the list's comparator, two values can be
+// equal to the list while differing in other fields; match decides on those.
+// match runs under the write lock and must not call methods on s.
+func (s *SyncSkipList[T]) CompareAndDelete(val T, match func(stored T) bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, found := s.list.SearchByValue(val)
+	if !found || !match(node.val) {
+		return false
+	}
+	s.list.Delete(val)
+	return true
+}
+
+// Update atomically reads, modifies and writes the element equal to val. fn is
+// called with the stored element, or the zero value and false if there is none.
+// If fn returns keep == false the element is deleted; otherwise newVal replaces
+// it, together with any other element equal to newVal.
+//
+// fn runs under the write lock and must not call methods on s.
+func (s *SyncSkipList[T]) Update(val T, fn func(old T, found bool) (newVal T, keep bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var old T
+	node, found := s.list.SearchByValue(val)
+	if found {
+		old = node.val
+	}
+
+	newVal, keep := fn(old, found)
+	switch {
+	case !keep:
+		if found {
+			s.list.Delete(val)
+		}
+		return
+	case found && s.list.comparator(old, newVal) == 0:
+		// same position in the order, so keep the node and its tower
+		node.val = newVal
+		return
+	case found:
+		s.list.Delete(val)
+	}
+
+	if node, exists := s.list.SearchByValue(newVal); exists {
+		node.val = newVal
+		return
+	}
+	s.list.Add(newVal)
+}
+
+// View calls fn with read-only access to the list under the read lock, so
+// every call fn makes through ro sees the same state. Nodes obtained through ro
+// must not be used after fn returns.
+//
+// fn must not call any method on s, reads included: sync.RWMutex read locks
+// are not reentrant, and a nested read lock deadlocks as soon as a writer is
+// waiting.
+func (s *SyncSkipList[T]) View(fn func(ro ReadOnly[T])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(readOnly[T]{s.list})
+}
+
+// SearchByValue returns the stored element equal to val.
+func (s *SyncSkipList[T]) SearchByValue(val T) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return nodeValue(s.list.SearchByValue(val))
+}
+
+// SearchByRank returns the element at the given 1-indexed rank.
+func (s *SyncSkipList[T]) SearchByRank(rank int) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return nodeValue(s.list.SearchByRank(rank))
+}
+
+// SearchByRevRank returns the element at the given 1-indexed rank, counted
+// from the largest element.
+func (s *SyncSkipList[T]) SearchByRevRank(rank int) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return nodeValue(s.list.SearchByRevRank(rank))
+}
+
+// GetLowerBound returns the first element greater than or equal to val.
+func (s *SyncSkipList[T]) GetLowerBound(val T) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return nodeValue(s.list.GetLowerBound(val))
+}
+
+// GetRank returns the 1-indexed rank of item.
+func (s *SyncSkipList[T]) GetRank(item T) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.GetRank(item)
+}
+
+// GetRevRank returns the 1-indexed rank of item, counted from the largest element.
+func (s *SyncSkipList[T]) GetRevRank(item T) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.GetRevRank(item)
+}
+
+// Contains checks if val exists in the list.
+func (s *SyncSkipList[T]) Contains(val T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.Contains(val)
+}
+
+// Len returns the number of elements in the list.
+func (s *SyncSkipList[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.Len()
+}
+
+// IsEmpty returns true if the list contains no elements.
+func (s *SyncSkipList[T]) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.IsEmpty()
+}
+
+// Range iterates over all elements in ascending order under the read lock, so
+// the iteration sees a consistent state. If fn returns false, iteration stops.
+// As with View, fn must not call any method on s.
+func (s *SyncSkipList[T]) Range(fn func(val T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.list.Range(fn)
+}
+
+// readOnly exposes only the ReadOnly methods of a list. Unlike the *SkipList
+// itself, it cannot be type-asserted to reach the write methods.
+type readOnly[T any] struct {
+	list *SkipList[T]
+}
+
+func (ro readOnly[T]) Len() int {
+	return ro.list.Len()
+}
+
+func (ro readOnly[T]) IsEmpty() bool {
+	return ro.list.IsEmpty()
+}
+
+func (ro readOnly[T]) Contains(val T) bool {
+	return ro.list.Contains(val)
+}
+
+func (ro readOnly[T]) SearchByValue(val T) (*Node[T], bool) {
+	return ro.list.SearchByValue(val)
+}
+
+func (ro readOnly[T]) SearchByRank(rank int) (*Node[T], bool) {
+	return ro.list.SearchByRank(rank)
+}
+
+func (ro readOnly[T]) SearchByRevRank(rank int) (*Node[T], bool) {
+	return ro.list.SearchByRevRank(rank)
+}
+
+func (ro readOnly[T]) GetLowerBound(val T) (*Node[T], bool) {
+	return ro.list.GetLowerBound(val)
+}
+
+func (ro readOnly[T]) GetRank(item T) (int, bool) {
+	return ro.list.GetRank(item)
+}
+
+func (ro readOnly[T]) GetRevRank(item T) (int, bool) {
+	return ro.list.GetRevRank(item)
+}
+
+func (ro readOnly[T]) Range(fn func(val T) bool) {
+	ro.list.Range(fn)
+}
+
+// nodeValue unwraps the value of a search result.
+func nodeValue[T any](node *Node[T], found bool) (T, bool) {
+	if !found {
+		var zero T
+		return zero, false
+	}
+	return node.val, true
+}
@@ -0,0 +1,182 @@
+package skiplist
+
+import (
+	"cmp"
+	"sync"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// SyncSkipList helpers
+// ------------------------------------------------------------
+
+// account is ordered by id only, so two accounts with the same id are equal to
+// the list even if their balances differ.
+type account struct {
+	id      string
+	balance int
+}
+
+func (a account) Compare(other account) int {
+	return cmp.Compare(a.id, other.id)
+}
+
+// ------------------------------------------------------------
+// SyncSkipList tests
+// ------------------------------------------------------------
+
+func TestSyncSkipList_GetOrAdd(t *testing.T) {
+	s := NewComparableSyncSkipList[account]()
+
+	if got, loaded := s.GetOrAdd(account{"a", 1}); loaded || got.balance != 1 {
+		t.Fatalf("first GetOrAdd should add, got %+v (loaded=%v)", got, loaded)
+	}
+	if got, loaded := s.GetOrAdd(account{"a", 2}); !loaded || got.balance != 1 {
+		t.Fatalf("second GetOrAdd should load the stored value, got %+v (loaded=%v)", got, loaded)
+	}
+	if s.Len() != 1 {
+		t.Fatalf("expected 1 element, got %d", s.Len())
+	}
+}
+
+func TestSyncSkipList_CompareAndDelete(t *testing.T) {
+	s := NewComparableSyncSkipList[account]()
+	s.Add(account{"a", 10})
+
+	hasBalance := func(want int) func(account) bool {
+		return func(stored account) bool { return stored.balance == want }
+	}
+	if s.CompareAndDelete(account{id: "a"}, hasBalance(5)) {
+		t.Fatal("CompareAndDelete should not delete when match fails")
+	}
+	if s.CompareAndDelete(account{id: "missing"}, hasBalance(0)) {
+		t.Fatal("CompareAndDelete of a missing value should report false")
+	}
+	if !s.CompareAndDelete(account{id: "a"}, hasBalance(10)) || s.Contains(account{id: "a"}) {
+		t.Fatal("CompareAndDelete should delete when match succeeds")
+	}
+}
+
+func TestSyncSkipList_Update(t *testing.T) {
+	s := NewComparableSyncSkipList[account]()
+	s.Add(account{"a", 1})
+	s.Add(account{"c", 3})
+
+	deposit := func(amount int) func(account, bool) (account, bool) {
+		return func(old account, found bool) (account, bool) {
+			old.balance += amount
+			return old, true
+		}
+	}
+
+	s.Update(account{id: "a"}, deposit(5))
+	if got, _ := s.SearchByValue(account{id: "a"}); got.balance != 6 {
+		t.Fatalf("in-place update: got %+v", got)
+	}
+
+	// moving an element to a new key replaces whatever was stored there
+	s.Update(account{id: "a"}, func(old account, _ bool) (account, bool) {
+		return account{"c", old.balance}, true
+	})
+	if s.Contains(account{id: "a"}) || s.Len() != 1 {
+		t.Fatalf("a should have been moved onto c, Len=%d", s.Len())
+	}
+	if got, _ := s.SearchByValue(account{id: "c"}); got.balance != 6 {
+		t.Fatalf("moved value should replace c, got %+v", got)
+	}
+
+	s.Update(account{id: "c"}, func(account, bool) (account, bool) { return account{}, false })
+	if !s.IsEmpty() {
+		t.Fatal("returning keep=false should delete the element")
+	}
+
+	s.Update(account{id: "b"}, func(old account, found bool) (account, bool) {
+		if found {
+			t.Fatal("missing element should be reported as not found")
+		}
+		return account{"b", 1}, true
+	})
+	if got, ok := s.SearchByRank(1); !ok || got != (account{"b", 1}) {
+		t.Fatalf("Update of a missing element should insert it, got %+v", got)
+	}
+}
+
+func TestSyncSkipList_ViewIsConsistent(t *testing.T) {
+	s := NewSyncSkipList[int]()
+	for i := 1; i <= 10; i++ {
+		s.Add(i)
+	}
+
+	s.View(func(ro ReadOnly[int]) {
+		node, _ := ro.SearchByRank(ro.Len())
+		if rank, _ := ro.GetRank(node.Value()); rank != ro.Len() {
+			t.Fatalf("rank of the last element should be %d, got %d", ro.Len(), rank)
+		}
+	})
+}
+
+func TestSyncSkipList_ConcurrentAccess(t *testing.T) {
+	s := NewSyncSkipList[int]()
+	const writers, readers, perWriter = 4, 4, 500
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				v := w*perWriter + i
+				s.Add(v)
+				if i%2 == 1 {
+					s.Delete(v)
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				// a consistent view always sees an ascending sequence whose
+				// length matches Len
+				s.View(func(ro ReadOnly[int]) {
+					n, prev := 0, -1
+					ro.Range(func(v int) bool {
+						if v <= prev {
+							t.Errorf("Range out of order: %d after %d", v, prev)
+						}
+						prev = v
+						n++
+						return true
+					})
+					if n != ro.Len() {
+						t.Errorf("Range saw %d elements, Len is %d", n, ro.Len())
+					}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Len() != writers*perWriter/2 {
+		t.Fatalf("expected %d elements, got %d", writers*perWriter/2, s.Len())
+	}
+}
+
+func TestSyncSkipList_ViewIsReadOnly(t *testing.T) {
+	s := NewSyncSkipList[int]()
+	s.Add(1)
+
+	s.View(func(ro ReadOnly[int]) {
+		if _, ok := ro.(*SkipList[int]); ok {
+			t.Fatal("View must not hand out the underlying *SkipList")
+		}
+		if _, ok := ro.(interface{ Add(int) }); ok {
+			t.Fatal("the value View hands out must not have write methods")
+		}
+		if !ro.Contains(1) || ro.Len() != 1 {
+			t.Fatal("reads through ro should see the list")
+		}
+	})
+}
@@ -0,0 +1,453 @@
+package skiplist
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+)
+
+// ErrBadTable is returned when a sorted table is truncated or fails a checksum.
+var ErrBadTable = errors.New("skiplist: bad sorted table")
+
+// TableBlockSize is the size a data block of a sorted table is filled to
+// before a new one is started.
+const TableBlockSize = 4096
+
+// A sorted table is a sequence of data blocks, an index block and a footer:
+//
+//	data block  entries, then a 4-byte CRC-32C of the entries
+//	entry       shared uvarint, unshared uvarint, value length uvarint,
+//	            the key bytes not shared with the previous key, the value
+//	index block per data block: last key length uvarint, last key,
+//	            offset uvarint, length uvarint; then a 4-byte CRC-32C
+//	footer      index offset, index length and entry count as 8-byte
+//	            little-endian integers, then the magic "SKTABLE1"
+//
+// Keys are prefix-compressed against the previous key of the same block; the
+// first key of every block is stored whole, so each block decodes on its own.
+const (
+	tableMagic      = "SKTABLE1"
+	tableFooterSize = 3*8 + len(tableMagic)
+)
+
+// UseCodecs sets the codecs used to encode keys and values when writing a
+// sorted table. Either may be nil for key or value types with a built-in codec.
+func (m *SkipMap[K, V]) UseCodecs(keys Codec[K], values Codec[V]) {
+	m.keyCodec, m.valueCodec = keys, values
+}
+
+// WriteSortedTable writes the map's entries to w as an immutable sorted table,
+// in the key order of its level-0 traversal. Open the result with OpenTable.
+//
+// Keys and values are encoded with the codecs set by UseCodecs, or the built-in
+// codec of their type.
+func (m *SkipMap[K, V]) WriteSortedTable(w io.Writer) error {
+	tw, err := NewTableWriter(w, m.keyCodec, m.valueCodec)
+	if err != nil {
+		return err
+	}
+	for node := m.list.head.levels[0].next; node != nil; node = node.levels[0].next {
+		if err := tw.Add(node.val.key, node.val.value); err != nil {
+			return err
+		}
+	}
+	return tw.Finish()
+}
+
+// TableWriter streams entries into a sorted table without holding them in a
+// map, for example to write the merge of several tables. Entries must be added
+// in strictly ascending key order; the writer does not check.
+type TableWriter[K, V any] struct {
+	tw         tableWriter
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+}
+
+// NewTableWriter returns a writer of a sorted table to w. A nil codec selects
+// the built-in codec of the type.
+func NewTableWriter[K, V any](w io.Writer, keys Codec[K], values Codec[V]) (*TableWriter[K, V], error) {
+	keyCodec, err := codecOrBuiltIn(keys)
+	if err != nil {
+		return nil, err
+	}
+	valueCodec, err := codecOrBuiltIn(values)
+	if err != nil {
+		return nil, err
+	}
+	return &TableWriter[K, V]{tw: tableWriter{w: w}, keyCodec: keyCodec, valueCodec: valueCodec}, nil
+}
+
+// Add appends an entry to the table.
+func (w *TableWriter[K, V]) Add(key K, value V) error {
+	rawKey, err := w.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	rawValue, err := w.valueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return w.tw.add(rawKey, rawValue)
+}
+
+// Finish writes the last data block, the index and the footer. The writer
+// must not be used afterwards.
+func (w *TableWriter[K, V]) Finish() error {
+	return w.tw.finish()
+}
+
+// tableWriter buffers one data block at a time and remembers where each block
+// went for the index.
+type tableWriter struct {
+	w       io.Writer
+	offset  uint64
+	count   uint64
+	block   []byte
+	prevKey []byte
+	index   []byte
+}
+
+func (tw *tableWriter) add(key, value []byte) error {
+	shared := 0
+	if len(tw.block) > 0 {
+		for shared < min(len(key), len(tw.prevKey)) && key[shared] == tw.prevKey[shared] {
+			shared++
+		}
+	}
+
+	tw.block = binary.AppendUvarint(tw.block, uint64(shared))
+	tw.block = binary.AppendUvarint(tw.block, uint64(len(key)-shared))
+	tw.block = binary.AppendUvarint(tw.block, uint64(len(value)))
+	tw.block = append(tw.block, key[shared:]...)
+	tw.block = append(tw.block, value...)
+	tw.prevKey = append(tw.prevKey[:0], key...)
+	tw.count++
+
+	if len(tw.block) >= TableBlockSize {
+		return tw.flushBlock()
+	}
+	return nil
+}
+
+// flushBlock writes the pending data block and adds its last key to the index.
+func (tw *tableWriter) flushBlock() error {
+	if len(tw.block) == 0 {
+		return nil
+	}
+	n, err := tw.writeBlock(tw.block)
+	if err != nil {
+		return err
+	}
+
+	tw.index = binary.AppendUvarint(tw.index, uint64(len(tw.prevKey)))
+	tw.index = append(tw.index, tw.prevKey...)
+	tw.index = binary.AppendUvarint(tw.index, tw.offset-n)
+	tw.index = binary.AppendUvarint(tw.index, n)
+	tw.block = tw.block[:0]
+	return nil
+}
+
+// writeBlock writes data followed by its checksum and returns the bytes written.
+func (tw *tableWriter) writeBlock(data []byte) (uint64, error) {
+	data = binary.LittleEndian.AppendUint32(data, crc32.Checksum(data, snapshotTable))
+	if _, err := tw.w.Write(data); err != nil {
+		return 0, err
+	}
+	tw.offset += uint64(len(data))
+	return uint64(len(data)), nil
+}
+
+func (tw *tableWriter) finish() error {
+	if err := tw.flushBlock(); err != nil {
+		return err
+	}
+	indexSize, err := tw.writeBlock(tw.index)
+	if err != nil {
+		return err
+	}
+
+	footer := binary.LittleEndian.AppendUint64(nil, tw.offset-indexSize)
+	footer = binary.LittleEndian.AppendUint64(footer, indexSize)
+	footer = binary.LittleEndian.AppendUint64(footer, tw.count)
+	footer = append(footer, tableMagic...)
+	_, err = tw.w.Write(footer)
+	return err
+}
+
+// Table is a read-only sorted table written by SkipMap.WriteSortedTable. The
+// index is held in memory; data blocks are read from the underlying
+// io.ReaderAt on demand and verified against their checksums.
+//
+// A Table is safe for concurrent use if its io.ReaderAt is; iterators are not.
+type Table[K, V any] struct {
+	r          io.ReaderAt
+	comparator Comparator[K]
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+	// lastKeys[i] is the largest key of the data block at blocks[i].
+	lastKeys []K
+	blocks   []blockHandle
+	count    int
+}
+
+type blockHandle struct {
+	offset, size uint64
+}
+
+// within reports whether the block lies entirely before end, without
+// overflowing on corrupt handles.
+func (h blockHandle) within(end uint64) bool {
+	return h.offset <= end && h.size <= end-h.offset
+}
+
+type tableEntry[K, V any] struct {
+	key   K
+	value V
+}
+
+// OpenTable opens a sorted table of size bytes with ordered keys, decoding
+// keys and values with their built-in codecs.
+func OpenTable[K cmp.Ordered, V any](r io.ReaderAt, size int64) (*Table[K, V], error) {
+	return OpenTableFunc[K, V](r, size, cmp.Compare[K], nil, nil)
+}
+
+// OpenBytesTable opens a sorted table with []byte keys compared by
+// bytes.Compare, like a map created by NewBytesMap.
+func OpenBytesTable[V any](r io.ReaderAt, size int64) (*Table[[]byte, V], error) {
+	return OpenTableFunc[[]byte, V](r, size, bytes.Compare, nil, nil)
+}
+
+// OpenTableFunc opens a sorted table whose keys are ordered by comparator.
+// keys and values must match the codecs the table was written with; nil selects
+// the built-in codec of the type.
+func OpenTableFunc[K, V any](r io.ReaderAt, size int64, comparator Comparator[K], keys Codec[K], values Codec[V]) (*Table[K, V], error) {
+	keyCodec, err := codecOrBuiltIn(keys)
+	if err != nil {
+		return nil, err
+	}
+	valueCodec, err := codecOrBuiltIn(values)
+	if err != nil {
+		return nil, err
+	}
+
+	if size < int64(tableFooterSize) {
+		return nil, fmt.Errorf("%w: too short", ErrBadTable)
+	}
+	footer := make([]byte, tableFooterSize)
+	if _, err := r.ReadAt(footer, size-int64(tableFooterSize)); err != nil {
+		return nil, err
+	}
+	if string(footer[3*8:]) != tableMagic {
+		return nil, fmt.Errorf("%w: missing footer", ErrBadTable)
+	}
+
+	t := &Table[K, V]{
+		r:          r,
+		comparator: comparator,
+		keyCodec:   keyCodec,
+		valueCodec: valueCodec,
+		count:      int(binary.LittleEndian.Uint64(footer[16:])),
+	}
+	indexHandle := blockHandle{
+		offset: binary.LittleEndian.Uint64(footer[0:]),
+		size:   binary.LittleEndian.Uint64(footer[8:]),
+	}
+	// the index ends at the footer and the data blocks end at the index; each
+	// handle is checked before anything is allocated for it
+	indexEnd := uint64(size) - uint64(tableFooterSize)
+	if !indexHandle.within(indexEnd) || indexHandle.offset+indexHandle.size != indexEnd {
+		return nil, fmt.Errorf("%w: bad index location", ErrBadTable)
+	}
+	if uint64(t.count) > uint64(size) {
+		return nil, fmt.Errorf("%w: bad entry count", ErrBadTable)
+	}
+	index, err := t.readBlock(indexHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	d := byteDecoder{data: index}
+	for len(d.data) > 0 && d.err == nil {
+		lastKey, err := keyCodec.Decode(d.bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%w: index: %v", ErrBadTable, err)
+		}
+		handle := blockHandle{offset: uint64(d.uvarint()), size: uint64(d.uvarint())}
+		if d.err == nil && !handle.within(indexHandle.offset) {
+			return nil, fmt.Errorf("%w: index: block handle out of range", ErrBadTable)
+		}
+		t.lastKeys = append(t.lastKeys, lastKey)
+		t.blocks = append(t.blocks, handle)
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("%w: index: %v", ErrBadTable, d.err)
+	}
+	return t, nil
+}
+
+// Len returns the number of entries in the table.
+func (t *Table[K, V]) Len() int {
+	return t.count
+}
+
+// Get returns the value stored for key. Only the one data block that can hold
+// key is read.
+func (t *Table[K, V]) Get(key K) (V, bool, error) {
+	it := t.NewIterator()
+	it.Seek(key)
+	if !it.Valid() || t.comparator(it.Key(), key) != 0 {
+		var zero V
+		return zero, false, it.Err()
+	}
+	return it.Value(), true, nil
+}
+
+// Seek returns the first entry whose key is greater than or equal to key.
+func (t *Table[K, V]) Seek(key K) (K, V, bool, error) {
+	it := t.NewIterator()
+	it.Seek(key)
+	if !it.Valid() {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false, it.Err()
+	}
+	return it.Key(), it.Value(), true, nil
+}
+
+// NewIterator returns an iterator over the table.
+func (t *Table[K, V]) NewIterator() *TableIterator[K, V] {
+	return &TableIterator[K, V]{t: t}
+}
+
+// readBlock reads the block at h and verifies its checksum, returning the
+// block without it.
+func (t *Table[K, V]) readBlock(h blockHandle) ([]byte, error) {
+	if h.size < 4 {
+		return nil, fmt.Errorf("%w: block at %d is too short", ErrBadTable, h.offset)
+	}
+	buf := make([]byte, h.size)
+	if _, err := t.r.ReadAt(buf, int64(h.offset)); err != nil {
+		return nil, err
+	}
+	data, sum := buf[:len(buf)-4], binary.LittleEndian.Uint32(buf[len(buf)-4:])
+	if crc32.Checksum(data, snapshotTable) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch in block at %d", ErrBadTable, h.offset)
+	}
+	return data, nil
+}
+
+// loadBlock reads and decodes every entry of the i-th data block.
+func (t *Table[K, V]) loadBlock(i int) ([]tableEntry[K, V], error) {
+	data, err := t.readBlock(t.blocks[i])
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []tableEntry[K, V]
+	var prevKey []byte
+	d := byteDecoder{data: data}
+	for len(d.data) > 0 {
+		shared, unshared, valueLen := d.uvarint(), d.uvarint(), d.uvarint()
+		if d.err != nil || shared > len(prevKey) || unshared > len(d.data) || valueLen > len(d.data)-unshared {
+			return nil, fmt.Errorf("%w: corrupt entry in block at %d", ErrBadTable, t.blocks[i].offset)
+		}
+		// a fresh slice per key, since a codec may keep the bytes it decodes
+		rawKey := make([]byte, shared+unshared)
+		copy(rawKey, prevKey[:shared])
+		copy(rawKey[shared:], d.data[:unshared])
+		rawValue := d.data[unshared : unshared+valueLen]
+		d.data = d.data[unshared+valueLen:]
+
+		key, err := t.keyCodec.Decode(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key: %v", ErrBadTable, err)
+		}
+		value, err := t.valueCodec.Decode(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: value: %v", ErrBadTable, err)
+		}
+		entries = append(entries, tableEntry[K, V]{key: key, value: value})
+		prevKey = rawKey
+	}
+	return entries, nil
+}
+
+// TableIterator walks the entries of a Table in key order, reading one data
+// block at a time. It implements Iterator; since reads can fail, check Err when
+// the iterator becomes invalid.
+type TableIterator[K, V any] struct {
+	t       *Table[K, V]
+	block   int
+	entries []tableEntry[K, V]
+	pos     int
+	err     error
+}
+
+var _ Iterator[int, int] = (*TableIterator[int, int])(nil)
+
+// First positions the iterator at the smallest key.
+func (it *TableIterator[K, V]) First() {
+	it.load(0, 0)
+}
+
+// Seek positions the iterator at the first key greater than or equal to key.
+func (it *TableIterator[K, V]) Seek(key K) {
+	// the first block whose last key is not below key holds the target
+	block := sort.Search(len(it.t.lastKeys), func(i int) bool {
+		return it.t.comparator(it.t.lastKeys[i], key) >= 0
+	})
+	if !it.load(block, 0) {
+		return
+	}
+	it.pos = sort.Search(len(it.entries), func(i int) bool {
+		return it.t.comparator(it.entries[i].key, key) >= 0
+	})
+}
+
+// Next moves the iterator to the next key. It is a no-op if the iterator is
+// not Valid.
+func (it *TableIterator[K, V]) Next() {
+	if !it.Valid() {
+		return
+	}
+	it.pos++
+	if it.pos == len(it.entries) {
+		it.load(it.block+1, 0)
+	}
+}
+
+// Valid reports whether the iterator is positioned at an entry.
+func (it *TableIterator[K, V]) Valid() bool {
+	return it.err == nil && it.pos < len(it.entries)
+}
+
+// Key returns the current key.
+func (it *TableIterator[K, V]) Key() K {
+	return it.entries[it.pos].key
+}
+
+// Value returns the current value.
+func (it *TableIterator[K, V]) Value() V {
+	return it.entries[it.pos].value
+}
+
+// Err returns the error that made the iterator invalid, if any.
+func (it *TableIterator[K, V]) Err() error {
+	return it.err
+}
+
+// load positions the iterator at entry pos of the given block and reports
+// whether that block exists and was read.
+func (it *TableIterator[K, V]) load(block, pos int) bool {
+	it.block, it.entries, it.pos = block, nil, pos
+	if block >= len(it.t.blocks) {
+		return false
+	}
+	it.entries, it.err = it.t.loadBlock(block)
+	return it.err == nil
+}
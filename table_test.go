@@ -0,0 +1,263 @@
+package skiplist
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Sorted table helpers
+// ------------------------------------------------------------
+
+// writeTable writes m as a sorted table and returns its bytes.
+func writeTable[K, V any](t *testing.T, m *SkipMap[K, V]) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := m.WriteSortedTable(&buf); err != nil {
+		t.Fatalf("WriteSortedTable returned %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newKeyMap returns a bytes map with keys "key-000000" to "key-<n-1>", each
+// mapped to the byte string "value-<i>".
+func newKeyMap(n int) *SkipMap[[]byte, []byte] {
+	m := NewBytesMap[[]byte](CopyKeys)
+	for i := 0; i < n; i++ {
+		m.Set([]byte(fmt.Sprintf("key-%06d", i)), []byte(fmt.Sprintf("value-%d", i)))
+	}
+	return m
+}
+
+// rewriteIndex replaces the index of a single-block table with one entry for
+// lastKey pointing at h, keeping the checksums and the footer consistent.
+func rewriteIndex(data []byte, lastKey string, h blockHandle) []byte {
+	footer := data[len(data)-tableFooterSize:]
+	indexOff := binary.LittleEndian.Uint64(footer[0:])
+
+	index := binary.AppendUvarint(nil, uint64(len(lastKey)))
+	index = append(index, lastKey...)
+	index = binary.AppendUvarint(index, h.offset)
+	index = binary.AppendUvarint(index, h.size)
+	index = binary.LittleEndian.AppendUint32(index, crc32.Checksum(index, snapshotTable))
+
+	out := append(bytes.Clone(data[:indexOff]), index...)
+	out = binary.LittleEndian.AppendUint64(out, indexOff)
+	out = binary.LittleEndian.AppendUint64(out, uint64(len(index)))
+	return append(out, footer[16:]...)
+}
+
+// ------------------------------------------------------------
+// Sorted table tests
+// ------------------------------------------------------------
+
+func TestTable_GetAndSeek(t *testing.T) {
+	m := newKeyMap(5000)
+	data := writeTable(t, m)
+
+	table, err := OpenBytesTable[[]byte](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenBytesTable returned %v", err)
+	}
+	if table.Len() != 5000 || len(table.blocks) < 2 {
+		t.Fatalf("expected 5000 entries over several blocks, got %d in %d blocks", table.Len(), len(table.blocks))
+	}
+
+	for _, i := range []int{0, 1, 2500, 4999} {
+		value, found, err := table.Get([]byte(fmt.Sprintf("key-%06d", i)))
+		if err != nil || !found || string(value) != fmt.Sprintf("value-%d", i) {
+			t.Fatalf("Get(%d) = %q, %v, %v", i, value, found, err)
+		}
+	}
+	if _, found, err := table.Get([]byte("key-0025005")); found || err != nil {
+		t.Fatalf("Get of a missing key should report not found, got %v, %v", found, err)
+	}
+
+	key, _, found, _ := table.Seek([]byte("key-0025005"))
+	if !found || string(key) != "key-002501" {
+		t.Fatalf("Seek should land on key-002501, got %q", key)
+	}
+	if _, _, found, _ := table.Seek([]byte("key-999999")); found {
+		t.Fatalf("Seek past the last key should report not found")
+	}
+}
+
+func TestTable_IteratorMatchesMap(t *testing.T) {
+	m := newKeyMap(3000)
+	data := writeTable(t, m)
+	table, err := OpenBytesTable[[]byte](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenBytesTable returned %v", err)
+	}
+
+	var raw int
+	it := table.NewIterator()
+	it.First()
+	m.Range(func(key, value []byte) bool {
+		raw += len(key) + len(value)
+		if !it.Valid() || !bytes.Equal(it.Key(), key) || !bytes.Equal(it.Value(), value) {
+			t.Fatalf("iterator diverges from the map at %q", key)
+		}
+		it.Next()
+		return true
+	})
+	if it.Valid() || it.Err() != nil {
+		t.Fatalf("iterator should be exhausted cleanly, err %v", it.Err())
+	}
+
+	// keys share most of their bytes, so prefix compression must pay off
+	if len(data) >= raw {
+		t.Fatalf("table is %d bytes, no smaller than the %d raw bytes", len(data), raw)
+	}
+
+	// iterate across a block boundary from the middle
+	it.Seek([]byte(fmt.Sprintf("key-%06d", 1000)))
+	for i := 1000; i < 1400; i++ {
+		if !it.Valid() || string(it.Key()) != fmt.Sprintf("key-%06d", i) {
+			t.Fatalf("expected key %d after seeking", i)
+		}
+		it.Next()
+	}
+}
+
+func TestTable_OrderedAndCustomTypes(t *testing.T) {
+	ints := NewSkipMap[int, string]()
+	for _, k := range []int{-5, 0, 12, 7} {
+		ints.Set(k, fmt.Sprint("v", k))
+	}
+	data := writeTable(t, ints)
+	intTable, err := OpenTable[int, string](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenTable returned %v", err)
+	}
+	if key, value, found, _ := intTable.Seek(1); !found || key != 7 || value != "v7" {
+		t.Fatalf("Seek(1) should find 7, got %d %q", key, value)
+	}
+
+	accounts := NewSkipMap[string, account]()
+	if _, err := OpenTableFunc[string, account](bytes.NewReader(data), int64(len(data)), cmp.Compare[string], nil, nil); !errors.Is(err, ErrNoCodec) {
+		t.Fatalf("expected ErrNoCodec, got %v", err)
+	}
+	accounts.UseCodecs(nil, accountCodec{})
+	accounts.Set("alice", account{id: "alice", balance: 10})
+	accounts.Set("bob", account{id: "bob", balance: 20})
+	data = writeTable(t, accounts)
+
+	table, err := OpenTableFunc(bytes.NewReader(data), int64(len(data)), cmp.Compare[string], nil, Codec[account](accountCodec{}))
+	if err != nil {
+		t.Fatalf("OpenTableFunc returned %v", err)
+	}
+	if value, found, _ := table.Get("bob"); !found || value.balance != 20 {
+		t.Fatalf("expected bob's balance 20, got %+v", value)
+	}
+}
+
+func TestTable_Empty(t *testing.T) {
+	data := writeTable(t, NewBytesMap[[]byte](CopyKeys))
+	table, err := OpenBytesTable[[]byte](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenBytesTable returned %v", err)
+	}
+	it := table.NewIterator()
+	it.First()
+	if table.Len() != 0 || it.Valid() {
+		t.Fatalf("an empty table should have no entries")
+	}
+}
+
+func TestTable_DetectsCorruption(t *testing.T) {
+	data := writeTable(t, newKeyMap(1000))
+
+	badFooter := bytes.Clone(data)
+	badFooter[len(badFooter)-1] = 'X'
+	if _, err := OpenBytesTable[[]byte](bytes.NewReader(badFooter), int64(len(badFooter))); !errors.Is(err, ErrBadTable) {
+		t.Fatalf("expected ErrBadTable for a bad footer, got %v", err)
+	}
+
+	badBlock := bytes.Clone(data)
+	badBlock[10] ^= 0x01
+	table, err := OpenBytesTable[[]byte](bytes.NewReader(badBlock), int64(len(badBlock)))
+	if err != nil {
+		t.Fatalf("the index is intact, OpenBytesTable returned %v", err)
+	}
+	if _, _, err := table.Get([]byte("key-000000")); !errors.Is(err, ErrBadTable) {
+		t.Fatalf("expected ErrBadTable reading the first block, got %v", err)
+	}
+	it := table.NewIterator()
+	it.First()
+	if it.Valid() || !errors.Is(it.Err(), ErrBadTable) {
+		t.Fatalf("iterator should stop with ErrBadTable, got %v", it.Err())
+	}
+
+	// later blocks are still readable
+	if _, found, err := table.Get([]byte("key-000999")); !found || err != nil {
+		t.Fatalf("Get from an intact block returned %v, %v", found, err)
+	}
+}
+
+func TestTable_RejectsOutOfRangeHandles(t *testing.T) {
+	m := NewBytesMap[[]byte](CopyKeys)
+	m.Set([]byte("a"), []byte("b"))
+	data := writeTable(t, m)
+	indexEnd := uint64(len(data) - tableFooterSize)
+
+	// an index handle whose offset and size wrap around to the footer
+	wrapped := bytes.Clone(data)
+	binary.LittleEndian.PutUint64(wrapped[indexEnd:], math.MaxUint64-9)
+	binary.LittleEndian.PutUint64(wrapped[indexEnd+8:], indexEnd+10)
+
+	// an entry count larger than the file
+	hugeCount := bytes.Clone(data)
+	binary.LittleEndian.PutUint64(hugeCount[indexEnd+16:], math.MaxUint64)
+
+	cases := map[string][]byte{
+		"wrapping index handle": wrapped,
+		"entry count":           hugeCount,
+		"block past the index":  rewriteIndex(data, "a", blockHandle{offset: 0, size: 1 << 62}),
+		"wrapping block handle": rewriteIndex(data, "a", blockHandle{offset: math.MaxInt64, size: math.MaxInt64}),
+	}
+	for name, table := range cases {
+		if _, err := OpenBytesTable[[]byte](bytes.NewReader(table), int64(len(table))); !errors.Is(err, ErrBadTable) {
+			t.Fatalf("%s: expected ErrBadTable, got %v", name, err)
+		}
+	}
+
+	// the rewritten index is accepted when its handle is right
+	blockSize := binary.LittleEndian.Uint64(data[indexEnd:])
+	good := rewriteIndex(data, "a", blockHandle{offset: 0, size: blockSize})
+	table, err := OpenBytesTable[[]byte](bytes.NewReader(good), int64(len(good)))
+	if err != nil {
+		t.Fatalf("OpenBytesTable returned %v", err)
+	}
+	if v, found, err := table.Get([]byte("a")); !found || err != nil || string(v) != "b" {
+		t.Fatalf("Get(a) = %q %v %v", v, found, err)
+	}
+}
+
+func FuzzOpenTable(f *testing.F) {
+	m := NewBytesMap[[]byte](CopyKeys)
+	for i := 0; i < 50; i++ {
+		m.Set([]byte(fmt.Sprintf("key-%03d", i)), []byte("value"))
+	}
+	var buf bytes.Buffer
+	m.WriteSortedTable(&buf)
+	f.Add(buf.Bytes())
+	f.Add(buf.Bytes()[:tableFooterSize])
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		table, err := OpenBytesTable[[]byte](bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return
+		}
+		it := table.NewIterator()
+		for it.First(); it.Valid(); it.Next() {
+		}
+		table.Get([]byte("key-025"))
+	})
+}
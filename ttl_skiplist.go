@@ -0,0 +1,260 @@
+package skiplist
+
+import (
+	"cmp"
+	"sync"
+	"time"
+)
+
+// Clock returns the current time. It is injected into TTLSkipList so tests can
+// control expiration without sleeping.
+type Clock func() time.Time
+
+type ttlItem[T any] struct {
+	val     T
+	expires time.Time // zero means the item never expires
+}
+
+// TTLSkipList is a skip list whose elements expire after a per-element
+// time-to-live.
+//
+// Elements are kept in two skip lists: one ordered by value for lookups and one
+// ordered by expiry time so expired elements can be removed from the front in
+// O(log n) each. Lookups and iteration filter out expired elements lazily, so an
+// expired element is never observed even before it has been swept by
+// ExpireBefore or the background janitor.
+//
+// All methods are safe for concurrent use.
+type TTLSkipList[T any] struct {
+	mu       sync.Mutex
+	values   *SkipList[ttlItem[T]]
+	expiries *SkipList[ttlItem[T]]
+	now      Clock
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewTTLSkipList creates a TTL skip list for ordered types. If clock is nil,
+// time.Now is used.
+func NewTTLSkipList[T cmp.Ordered](clock Clock) *TTLSkipList[T] {
+	return newTTLSkipList(cmp.Compare[T], clock)
+}
+
+// NewComparableTTLSkipList creates a TTL skip list for types that implement the
+// Comparable interface. If clock is nil, time.Now is used.
+func NewComparableTTLSkipList[T Comparable[T]](clock Clock) *TTLSkipList[T] {
+	return newTTLSkipList(func(a, b T) int {
+		return a.Compare(b)
+	}, clock)
+}
+
+func newTTLSkipList[T any](comparator Comparator[T], clock Clock) *TTLSkipList[T] {
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return &TTLSkipList[T]{
+		values: newSkipList(func(a, b ttlItem[T]) int {
+			return comparator(a.val, b.val)
+		}),
+		expiries: newSkipList(func(a, b ttlItem[T]) int {
+			if c := a.expires.Compare(b.expires); c != 0 {
+				return c
+			}
+			return comparator(a.val, b.val)
+		}),
+		now: clock,
+	}
+}
+
+// Add inserts val with the given time-to-live. Adding a value that is already
+// present replaces its expiry. A ttl <= 0 means the value never expires.
+func (tl *TTLSkipList[T]) Add(val T, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = tl.now().Add(ttl)
+	}
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if node, found := tl.values.SearchByValue(ttlItem[T]{val: val}); found {
+		if !node.val.expires.IsZero() {
+			tl.expiries.Delete(node.val)
+		}
+		node.val.expires = expires
+	} else {
+		tl.values.Add(ttlItem[T]{val: val, expires: expires})
+	}
+
+	if !expires.IsZero() {
+		tl.expiries.Add(ttlItem[T]{val: val, expires: expires})
+	}
+}
+
+// Delete removes val from the list. No-op if the value doesn't exist.
+func (tl *TTLSkipList[T]) Delete(val T) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	node, found := tl.values.SearchByValue(ttlItem[T]{val: val})
+	if !found {
+		return
+	}
+	if !node.val.expires.IsZero() {
+		tl.expiries.Delete(node.val)
+	}
+	tl.values.Delete(node.val)
+}
+
+// SearchByValue returns the stored value equal to val if it exists and has not
+// expired.
+func (tl *TTLSkipList[T]) SearchByValue(val T) (T, bool) {
+	now := tl.now()
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	node, found := tl.values.SearchByValue(ttlItem[T]{val: val})
+	if !found || node.val.expired(now) {
+		var zero T
+		return zero, false
+	}
+	return node.val.val, true
+}
+
+// Contains checks if a value exists and has not expired.
+func (tl *TTLSkipList[T]) Contains(val T) bool {
+	_, found := tl.SearchByValue(val)
+	return found
+}
+
+// TTL returns the remaining time-to-live of val. A value that never expires
+// reports a TTL of 0 and true.
+func (tl *TTLSkipList[T]) TTL(val T) (time.Duration, bool) {
+	now := tl.now()
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	node, found := tl.values.SearchByValue(ttlItem[T]{val: val})
+	if !found || node.val.expired(now) {
+		return 0, false
+	}
+	if node.val.expires.IsZero() {
+		return 0, true
+	}
+	return node.val.expires.Sub(now), true
+}
+
+// Range iterates over all unexpired elements in ascending order.
+// The function fn is called for each element. If fn returns false, iteration stops.
+// The list is locked during iteration, so fn must not call methods on it.
+func (tl *TTLSkipList[T]) Range(fn func(val T) bool) {
+	now := tl.now()
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	tl.values.Range(func(item ttlItem[T]) bool {
+		if item.expired(now) {
+			return true
+		}
+		return fn(item.val)
+	})
+}
+
+// Len returns the number of elements in the list, including expired elements
+// that have not been swept yet.
+func (tl *TTLSkipList[T]) Len() int {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	return tl.values.Len()
+}
+
+// ExpireBefore removes every element whose expiry is at or before now and
+// returns how many were removed.
+func (tl *TTLSkipList[T]) ExpireBefore(now time.Time) int {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	removed := 0
+	for {
+		node, found := tl.expiries.SearchByRank(1)
+		if !found || !node.val.expired(now) {
+			break
+		}
+
+		item := node.val
+		tl.expiries.Delete(item)
+		tl.values.Delete(item)
+		removed++
+	}
+	return removed
+}
+
+// StartJanitor starts a background goroutine that calls ExpireBefore with the
+// list's clock every interval. A running janitor is stopped first, and when
+// several calls race, the janitor of the last one to take the lock is the one
+// left running.
+//
+// StartJanitor panics if interval is not positive, as time.NewTicker does,
+// but in the caller's goroutine and before any janitor is stopped or started.
+func (tl *TTLSkipList[T]) StartJanitor(interval time.Duration) {
+	if interval <= 0 {
+		panic("skiplist: non-positive interval for StartJanitor")
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	// installing the new janitor and taking the old one happen under a single
+	// lock, so every janitor is owned by exactly one caller that stops it
+	tl.mu.Lock()
+	oldStop, oldDone := tl.stop, tl.done
+	tl.stop, tl.done = stop, done
+	tl.mu.Unlock()
+
+	// the old janitor may be waiting for the lock to sweep, so it is stopped
+	// after the lock is released
+	if oldStop != nil {
+		close(oldStop)
+		<-oldDone
+	}
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				tl.ExpireBefore(tl.now())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background janitor and waits for it to exit.
+// It is a no-op if no janitor is running.
+func (tl *TTLSkipList[T]) Stop() {
+	tl.mu.Lock()
+	stop, done := tl.stop, tl.done
+	tl.stop, tl.done = nil, nil
+	tl.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (item ttlItem[T]) expired(now time.Time) bool {
+	return !item.expires.IsZero() && !item.expires.After(now)
+}
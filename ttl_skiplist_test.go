@@ -0,0 +1,209 @@
+package skiplist
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ------------------------------------------------------------
+// TTL helpers
+// ------------------------------------------------------------
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func collectTTLValues(tl *TTLSkipList[int]) []int {
+	var vals []int
+	tl.Range(func(v int) bool {
+		vals = append(vals, v)
+		return true
+	})
+	return vals
+}
+
+// ------------------------------------------------------------
+// TTL tests
+// ------------------------------------------------------------
+
+func TestTTLSkipList_LazyExpiration(t *testing.T) {
+	clock := newFakeClock()
+	tl := NewTTLSkipList[int](clock.Now)
+
+	tl.Add(1, time.Second)
+	tl.Add(2, 3*time.Second)
+	tl.Add(3, 0) // never expires
+
+	clock.Advance(time.Second)
+
+	if tl.Contains(1) {
+		t.Fatal("1 should have expired")
+	}
+	if !tl.Contains(2) || !tl.Contains(3) {
+		t.Fatal("2 and 3 should still be live")
+	}
+	if got := collectTTLValues(tl); !slicesEqual(got, []int{2, 3}) {
+		t.Fatalf("Range should skip expired values, got %v", got)
+	}
+	// expired entries are only filtered, not removed
+	if tl.Len() != 3 {
+		t.Fatalf("Len should still count unswept entries, got %d", tl.Len())
+	}
+}
+
+func TestTTLSkipList_ExpireBefore(t *testing.T) {
+	clock := newFakeClock()
+	tl := NewTTLSkipList[int](clock.Now)
+
+	for i := 1; i <= 10; i++ {
+		tl.Add(i, time.Duration(i)*time.Second)
+	}
+	tl.Add(100, 0)
+
+	clock.Advance(4 * time.Second)
+	if removed := tl.ExpireBefore(clock.Now()); removed != 4 {
+		t.Fatalf("expected 4 removed, got %d", removed)
+	}
+	if tl.Len() != 7 {
+		t.Fatalf("expected 7 remaining, got %d", tl.Len())
+	}
+	if got := collectTTLValues(tl); !slicesEqual(got, []int{5, 6, 7, 8, 9, 10, 100}) {
+		t.Fatalf("unexpected values after ExpireBefore: %v", got)
+	}
+
+	clock.Advance(time.Hour)
+	tl.ExpireBefore(clock.Now())
+	if got := collectTTLValues(tl); !slicesEqual(got, []int{100}) {
+		t.Fatalf("only the non-expiring value should remain, got %v", got)
+	}
+}
+
+func TestTTLSkipList_AddRefreshesExpiry(t *testing.T) {
+	clock := newFakeClock()
+	tl := NewTTLSkipList[int](clock.Now)
+
+	tl.Add(7, time.Second)
+	clock.Advance(500 * time.Millisecond)
+	tl.Add(7, 2*time.Second)
+	clock.Advance(time.Second)
+
+	if removed := tl.ExpireBefore(clock.Now()); removed != 0 {
+		t.Fatalf("refreshed entry should not expire, removed %d", removed)
+	}
+	if ttl, ok := tl.TTL(7); !ok || ttl != time.Second {
+		t.Fatalf("expected remaining TTL of 1s, got %v (%v)", ttl, ok)
+	}
+
+	tl.Add(7, 0)
+	clock.Advance(time.Hour)
+	if removed := tl.ExpireBefore(clock.Now()); removed != 0 || !tl.Contains(7) {
+		t.Fatal("entry re-added without TTL should never expire")
+	}
+}
+
+func TestTTLSkipList_Delete(t *testing.T) {
+	clock := newFakeClock()
+	tl := NewTTLSkipList[int](clock.Now)
+
+	tl.Add(1, time.Second)
+	tl.Delete(1)
+	tl.Delete(42) // missing value is a no-op
+
+	if tl.Len() != 0 {
+		t.Fatalf("expected empty list, got %d", tl.Len())
+	}
+	clock.Advance(time.Hour)
+	if removed := tl.ExpireBefore(clock.Now()); removed != 0 {
+		t.Fatalf("deleted entry should not be expired again, removed %d", removed)
+	}
+}
+
+func TestTTLSkipList_Janitor(t *testing.T) {
+	clock := newFakeClock()
+	tl := NewTTLSkipList[int](clock.Now)
+	defer tl.Stop()
+
+	tl.Add(1, time.Second)
+	tl.Add(2, time.Hour)
+	tl.StartJanitor(time.Millisecond)
+
+	clock.Advance(time.Minute)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for tl.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("janitor did not sweep expired entry, Len=%d", tl.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	tl.Stop()
+	tl.Stop() // stopping twice is a no-op
+}
+
+func TestTTLSkipList_StartJanitorRejectsNonPositiveInterval(t *testing.T) {
+	tl := NewTTLSkipList[int](nil)
+	defer tl.Stop()
+	tl.StartJanitor(time.Hour)
+	running := tl.stop
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("StartJanitor(%v) should panic", interval)
+				}
+			}()
+			tl.StartJanitor(interval)
+		}()
+	}
+	// the running janitor is left alone
+	if tl.stop != running {
+		t.Fatal("a rejected StartJanitor replaced the running janitor")
+	}
+}
+
+func TestTTLSkipList_ConcurrentStartJanitor(t *testing.T) {
+	tl := NewTTLSkipList[int](nil)
+	before := runtime.NumGoroutine()
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tl.StartJanitor(time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	tl.Stop()
+
+	// every janitor but the last was stopped by the start that replaced it,
+	// and Stop stopped the last, so none may be left running
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running after Stop, started with %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
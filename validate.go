@@ -0,0 +1,114 @@
+package skiplist
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCorrupt is returned by Validate when the list breaks one of its
+// invariants.
+var ErrCorrupt = errors.New("skiplist: corrupt list")
+
+// Validate checks the structural invariants of the list and returns an error
+// wrapping ErrCorrupt that describes the first one broken:
+//   - level 0 holds exactly Len() elements in strictly ascending order
+//   - every higher level is a subsequence of level 0 that links, in order,
+//     every node whose tower reaches that level, so it is strictly ascending
+//     too and a subsequence of the level below
+//   - every span equals the number of level-0 steps its link covers, and a
+//     link to the end spans past the last element, so the head's spans above
+//     the top level equal Len()+1
+//   - the level counts and the top level agree with the towers
+//
+// Validate takes O(n) time and allocates a map of the nodes. It is meant for
+// tests and debug builds.
+func (sl *SkipList[T]) Validate() error {
+	if sl.head == nil {
+		if sl.length != 0 || sl.maxLevel != 0 || sl.levelCount != [MaxLevelCap + 1]int{} {
+			return fmt.Errorf("%w: a list without a head has length %d", ErrCorrupt, sl.length)
+		}
+		return nil
+	}
+	if len(sl.head.levels) != MaxLevelCap+1 {
+		return fmt.Errorf("%w: head has %d levels", ErrCorrupt, len(sl.head.levels))
+	}
+	if sl.maxLevel < 0 || sl.maxLevel > MaxLevelCap {
+		return fmt.Errorf("%w: max level %d is out of range", ErrCorrupt, sl.maxLevel)
+	}
+
+	// level 0 fixes the rank of every node; stopping past length also stops
+	// at a cycle
+	rankOf := make(map[*Node[T]]int, sl.length)
+	var towers [MaxLevelCap + 1]int
+	var prev *Node[T]
+	for node := sl.head.levels[0].next; node != nil; node = node.levels[0].next {
+		if len(rankOf) == sl.length {
+			return fmt.Errorf("%w: level 0 holds more than %d elements", ErrCorrupt, sl.length)
+		}
+		if len(node.levels) == 0 || len(node.levels) > MaxLevelCap+1 {
+			return fmt.Errorf("%w: node %v has a tower of %d levels", ErrCorrupt, node.val, len(node.levels))
+		}
+		if prev != nil && sl.comparator(prev.val, node.val) >= 0 {
+			return fmt.Errorf("%w: %v is not before %v at level 0", ErrCorrupt, prev.val, node.val)
+		}
+		rankOf[node] = len(rankOf) + 1
+		for lvl := range node.levels {
+			towers[lvl]++
+		}
+		prev = node
+	}
+	if len(rankOf) != sl.length {
+		return fmt.Errorf("%w: level 0 holds %d elements, length is %d", ErrCorrupt, len(rankOf), sl.length)
+	}
+	if towers != sl.levelCount {
+		return fmt.Errorf("%w: level counts %v do not match the towers %v", ErrCorrupt, sl.levelCount, towers)
+	}
+	top := 0
+	for top < MaxLevelCap && towers[top+1] > 0 {
+		top++
+	}
+	if sl.maxLevel != top {
+		return fmt.Errorf("%w: max level is %d, the tallest tower reaches %d", ErrCorrupt, sl.maxLevel, top)
+	}
+
+	for lvl := 0; lvl <= MaxLevelCap; lvl++ {
+		rank, linked := 0, 0
+		for node := sl.head; ; {
+			l := node.levels[lvl]
+			if l.next == nil {
+				if l.span != sl.length+1-rank {
+					return fmt.Errorf("%w: %s spans %d to the end at level %d, expected %d",
+						ErrCorrupt, sl.describe(node), l.span, lvl, sl.length+1-rank)
+				}
+				break
+			}
+			nextRank, ok := rankOf[l.next]
+			switch {
+			case !ok:
+				return fmt.Errorf("%w: %s links to a node missing from level 0 at level %d", ErrCorrupt, sl.describe(node), lvl)
+			case nextRank <= rank:
+				return fmt.Errorf("%w: %v is not after %s at level %d", ErrCorrupt, l.next.val, sl.describe(node), lvl)
+			case len(l.next.levels) <= lvl:
+				return fmt.Errorf("%w: %v is linked at level %d above its tower", ErrCorrupt, l.next.val, lvl)
+			case l.span != nextRank-rank:
+				return fmt.Errorf("%w: %s spans %d to %v at level %d, expected %d",
+					ErrCorrupt, sl.describe(node), l.span, l.next.val, lvl, nextRank-rank)
+			}
+			rank = nextRank
+			linked++
+			node = l.next
+		}
+		if linked != towers[lvl] {
+			return fmt.Errorf("%w: level %d links %d of the %d towers that reach it", ErrCorrupt, lvl, linked, towers[lvl])
+		}
+	}
+	return nil
+}
+
+// describe names a node in Validate errors.
+func (sl *SkipList[T]) describe(node *Node[T]) string {
+	if node == sl.head {
+		return "head"
+	}
+	return fmt.Sprint(node.val)
+}
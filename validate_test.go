@@ -0,0 +1,133 @@
+package skiplist
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// ------------------------------------------------------------
+// Validate helpers
+// ------------------------------------------------------------
+
+// validFixture builds 10 (level 1), 20 (level 0), 30 (level 2), 40 (level 0).
+func validFixture() *SkipList[int] {
+	s := NewSkipList[int]()
+	s.InsertAtLevel(10, 1)
+	s.InsertAtLevel(20, 0)
+	s.InsertAtLevel(30, 2)
+	s.InsertAtLevel(40, 0)
+	return s
+}
+
+// ------------------------------------------------------------
+// Validate tests
+// ------------------------------------------------------------
+
+func TestValidate_AcceptsListsAfterRandomOperations(t *testing.T) {
+	var zero SkipList[int]
+	if err := zero.Validate(); err != nil {
+		t.Fatalf("zero list: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(49))
+	heap, arena := NewSkipList[int](), NewSkipList[int]()
+	arena.UseArena(64)
+	for i := 0; i < 5000; i++ {
+		v := rng.Intn(500)
+		for _, sl := range []*SkipList[int]{heap, arena} {
+			switch rng.Intn(4) {
+			case 0:
+				sl.Delete(v)
+			case 1:
+				sl.deleteBetween(func(x int) bool { return x < v }, func(x int) bool { return x < v+5 })
+			default:
+				sl.Add(v)
+			}
+		}
+		if i%250 == 0 {
+			for _, sl := range []*SkipList[int]{heap, arena} {
+				if err := sl.Validate(); err != nil {
+					t.Fatalf("step %d: %v\n%s", i, err, dumpString(sl))
+				}
+			}
+		}
+	}
+
+	heap.Clear()
+	if err := heap.Validate(); err != nil {
+		t.Fatalf("cleared list: %v", err)
+	}
+}
+
+func TestValidate_ReportsBrokenInvariants(t *testing.T) {
+	cases := map[string]struct {
+		corrupt func(s *SkipList[int])
+		want    string
+	}{
+		"order": {
+			corrupt: func(s *SkipList[int]) { findNode(s, 20).val = 35 },
+			want:    "35 is not before 30 at level 0",
+		},
+		"span": {
+			corrupt: func(s *SkipList[int]) { findNode(s, 10).levels[1].span = 1 },
+			want:    "10 spans 1 to 30 at level 1, expected 2",
+		},
+		"head span above max level": {
+			corrupt: func(s *SkipList[int]) { s.head.levels[5].span = 4 },
+			want:    "head spans 4 to the end at level 5, expected 5",
+		},
+		"length": {
+			corrupt: func(s *SkipList[int]) { s.length = 5 },
+			want:    "level 0 holds 4 elements, length is 5",
+		},
+		"level count": {
+			corrupt: func(s *SkipList[int]) { s.levelCount[1] = 1 },
+			want:    "level counts",
+		},
+		"max level": {
+			corrupt: func(s *SkipList[int]) { s.maxLevel = 3 },
+			want:    "max level is 3, the tallest tower reaches 2",
+		},
+		"skipped tower": {
+			// level 1 bypasses 10 although its tower reaches level 1
+			corrupt: func(s *SkipList[int]) {
+				s.head.levels[1] = level[int]{next: findNode(s, 30), span: 3}
+			},
+			want: "level 1 links 1 of the 2 towers that reach it",
+		},
+		"not a subsequence": {
+			corrupt: func(s *SkipList[int]) {
+				s.head.levels[1].next = NewNode(15, 2)
+			},
+			want: "head links to a node missing from level 0 at level 1",
+		},
+		"link above tower": {
+			corrupt: func(s *SkipList[int]) {
+				ten := findNode(s, 10)
+				ten.levels[1] = level[int]{next: findNode(s, 20), span: 1}
+			},
+			want: "20 is linked at level 1 above its tower",
+		},
+		"cycle": {
+			corrupt: func(s *SkipList[int]) { findNode(s, 40).levels[0].next = findNode(s, 10) },
+			want:    "level 0 holds more than 4 elements",
+		},
+	}
+
+	if err := validFixture().Validate(); err != nil {
+		t.Fatalf("fixture should be valid: %v", err)
+	}
+	for name, tc := range cases {
+		s := validFixture()
+		tc.corrupt(s)
+		err := s.Validate()
+		if !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("%s: expected ErrCorrupt, got %v", name, err)
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected an error mentioning %q, got %q", name, tc.want, err)
+		}
+	}
+}